@@ -1,16 +1,27 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"os"
 	"subscription/internal/api/handlers"
 	"subscription/internal/api/server"
 	"subscription/internal/database"
+	"subscription/internal/events"
+	"subscription/internal/model"
+	"subscription/internal/notify"
 	"subscription/internal/repository"
+	"subscription/internal/scheduler"
 	"subscription/internal/service"
 
 	"github.com/joho/godotenv"
 )
 
+// @title        Subscription API
+// @version      1.0
+// @description  Сервис учета онлайн-подписок: подписки и их стоимость, бюджеты, напоминания, платежи и отчеты
+// @BasePath     /
 func main() {
 
 	if err := godotenv.Load(); err != nil {
@@ -22,7 +33,34 @@ func main() {
 
 	repo := repository.NewPgxRepository(db)
 
-	serv := service.NewService(repo)
+	serv := service.NewService(repo, events.NewLogPublisher())
+	serv.RegisterNotifier(model.ChannelLog, notify.NewLogNotifier())
+	serv.RegisterNotifier(model.ChannelWebhook, notify.NewWebhookNotifier())
+	if addr := os.Getenv("SMTP_ADDR"); addr != "" {
+		serv.RegisterNotifier(model.ChannelEmail, notify.NewSMTPNotifier(
+			addr,
+			os.Getenv("SMTP_FROM"),
+			os.Getenv("SMTP_USER"),
+			os.Getenv("SMTP_PASSWORD"),
+		))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Подписки, созданные до появления записей справочника, привязываются к ним при старте
+	if n, err := serv.LinkCatalog(ctx); err != nil {
+		log.Printf("Catalog link problem %v", err)
+	} else if n > 0 {
+		log.Printf("linked %d subscriptions to the catalog", n)
+	}
+
+	sched := scheduler.NewScheduler(repo)
+	if err := registerJobs(sched, serv); err != nil {
+		log.Printf("Scheduler problem %v", err)
+		return
+	}
+	sched.Start(ctx)
 
 	h := handlers.NewHTTPHandlers(serv)
 
@@ -33,3 +71,26 @@ func main() {
 	}
 
 }
+
+// registerJobs регистрирует фоновые задачи сервиса
+func registerJobs(sched *scheduler.Scheduler, serv *service.ServiceStore) error {
+	jobs := []struct {
+		name, spec, result string
+		run                func(ctx context.Context) (int, error)
+	}{
+		{"trial-conversion", "@hourly", "converted %d trials", serv.ConvertTrials},
+		{"renewal-reminders", "@hourly", "sent %d reminders", serv.SendReminders},
+		{"digests", "0 7 * * *", "sent %d digests", serv.SendDigests},
+		{"expiry-sweep", "@daily", "expired %d subscriptions", serv.ExpireSubscriptions},
+		{"catalog-link", "@daily", "linked %d subscriptions to the catalog", serv.LinkCatalog},
+	}
+	for _, j := range jobs {
+		if err := sched.Register(j.name, j.spec, func(ctx context.Context) (string, error) {
+			n, err := j.run(ctx)
+			return fmt.Sprintf(j.result, n), err
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package docs_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"sort"
+	"subscription/docs"
+	"testing"
+
+	"github.com/swaggo/swag"
+	"gopkg.in/yaml.v2"
+)
+
+// Настройки генерации должны совпадать с командой из README:
+// swag init -g cmd/main.go --parseInternal
+func annotationsSpec(t *testing.T) map[string]any {
+	t.Helper()
+
+	p := swag.New(swag.SetDebugger(log.New(io.Discard, "", 0)))
+	p.PropNamingStrategy = swag.CamelCase
+	p.ParseInternal = true
+	if err := p.ParseAPI("../", "cmd/main.go", 100); err != nil {
+		t.Fatalf("failed to parse annotations: %v", err)
+	}
+	b, err := json.Marshal(p.GetSwagger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return decode(t, b)
+}
+
+func decode(t *testing.T, b []byte) map[string]any {
+	t.Helper()
+
+	var spec map[string]any
+	if err := json.Unmarshal(b, &spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func readSpec(t *testing.T, name string) []byte {
+	t.Helper()
+
+	b, err := docs.Files.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// drift перечисляет операции и определения, которые различаются в двух спецификациях
+func drift(want, got map[string]any) []string {
+	var result []string
+	for _, section := range []string{"paths", "definitions"} {
+		w, _ := want[section].(map[string]any)
+		g, _ := got[section].(map[string]any)
+		keys := make(map[string]bool)
+		for k := range w {
+			keys[k] = true
+		}
+		for k := range g {
+			keys[k] = true
+		}
+		for k := range keys {
+			if !reflect.DeepEqual(w[k], g[k]) {
+				result = append(result, section+" "+k)
+			}
+		}
+	}
+	for k := range want {
+		if k != "paths" && k != "definitions" && !reflect.DeepEqual(want[k], got[k]) {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+func TestSpecMatchesAnnotations(t *testing.T) {
+
+	want := annotationsSpec(t)
+	got := decode(t, readSpec(t, "swagger.json"))
+
+	if diff := drift(want, got); len(diff) > 0 {
+		t.Fatalf("docs/swagger.json is out of date with handler annotations, regenerate it with swag init: %v", diff)
+	}
+}
+
+func TestGeneratedFilesInSync(t *testing.T) {
+
+	spec := decode(t, readSpec(t, "swagger.json"))
+
+	doc := decode(t, []byte(docs.SwaggerInfo.ReadDoc()))
+	if diff := drift(spec, doc); len(diff) > 0 {
+		t.Fatalf("docs/docs.go differs from docs/swagger.json: %v", diff)
+	}
+
+	var raw any
+	if err := yaml.Unmarshal(readSpec(t, "swagger.yaml"), &raw); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(stringKeys(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := drift(spec, decode(t, b)); len(diff) > 0 {
+		t.Fatalf("docs/swagger.yaml differs from docs/swagger.json: %v", diff)
+	}
+}
+
+// stringKeys приводит ключи словарей из yaml.v2 к строкам, чтобы результат можно было сериализовать в JSON
+func stringKeys(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = stringKeys(val)
+		}
+		return m
+	case []any:
+		for i := range v {
+			v[i] = stringKeys(v[i])
+		}
+	}
+	return v
+}
+
+func TestUIAssetsEmbedded(t *testing.T) {
+
+	for _, name := range []string{"ui/index.html", "ui/swagger-initializer.js", "ui/swagger-ui-bundle.js", "ui/swagger-ui.css"} {
+		if _, err := docs.Files.Open(name); err != nil {
+			t.Fatalf("%s is not embedded: %v", name, err)
+		}
+	}
+}
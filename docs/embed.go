@@ -0,0 +1,9 @@
+package docs
+
+import "embed"
+
+// Files спецификация OpenAPI, сгенерированная swag, и статика Swagger UI из каталога ui.
+// Встраиваются в бинарник, чтобы документация раздавалась сервером без внешних файлов.
+//
+//go:embed swagger.json swagger.yaml ui
+var Files embed.FS
@@ -1,18 +1,163 @@
 // datatransfer для запроса данных
 package datatransfer
 
-import "github.com/google/uuid"
+import (
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
 
 type DTOSubs struct {
-	ServiceName string `json:"service_name"`
-	Price       int    `json:"price"`
-	UserId      string `json:"user_id"`
-	StartDate   string `json:"start_date"`
-	EndDate     string `json:"end_date,omitempty"`
+	ServiceName string      `json:"service_name"`
+	Price       int         `json:"price"`
+	UserId      string      `json:"user_id"`
+	StartDate   string      `json:"start_date"`
+	EndDate     string      `json:"end_date,omitempty"`
+	HouseholdID string      `json:"household_id,omitempty"`
+	SplitType   string      `json:"split_type,omitempty"`
+	Members     []DTOMember `json:"members,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	PlanID      string      `json:"plan_id,omitempty"`
+	// TrialMonths длина бесплатного пробного периода в месяцах, начиная с start_date
+	TrialMonths int `json:"trial_months,omitempty"`
+	// TrialEndDate последний месяц пробного периода (MM-YYYY), альтернатива TrialMonths
+	TrialEndDate string `json:"trial_end_date,omitempty"`
+	// BillingCycle периодичность оплаты: monthly (по умолчанию), quarterly или yearly
+	BillingCycle string `json:"billing_cycle,omitempty"`
+}
+
+// DTOMember участник совместной подписки
+type DTOMember struct {
+	UserId string `json:"user_id"`
+	Share  int    `json:"share,omitempty"`
+}
+
+// DTOHousehold запрос на создание домохозяйства
+type DTOHousehold struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// DTOBudget запрос на создание или изменение бюджета
+type DTOBudget struct {
+	UserId       string `json:"user_id"`
+	ServiceName  string `json:"service_name,omitempty"`
+	Category     string `json:"category,omitempty"`
+	MonthlyLimit int    `json:"monthly_limit"`
+}
+
+// DTOCatalogEntry запрос на создание или изменение записи справочника сервисов
+type DTOCatalogEntry struct {
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases,omitempty"`
+	DefaultPrice int      `json:"default_price"`
+	Category     string   `json:"category,omitempty"`
+	Website      string   `json:"website,omitempty"`
+}
+
+// DTOPlan запрос на создание тарифа сервиса
+type DTOPlan struct {
+	Name  string `json:"name"`
+	Price int    `json:"price"`
+}
+
+// DTOChangePlan запрос на смену тарифа подписки с указанного месяца
+type DTOChangePlan struct {
+	PlanID        string `json:"plan_id"`
+	EffectiveDate string `json:"effective_date"`
+}
+
+// DTOPrice запрос на изменение цены подписки с указанного месяца
+type DTOPrice struct {
+	Price         int    `json:"price"`
+	EffectiveFrom string `json:"effective_from"`
+}
+
+// DTOStatusChange запрос на приостановку, возобновление или отмену подписки.
+// Если дата не указана, изменение действует с текущего месяца.
+type DTOStatusChange struct {
+	Reason        string `json:"reason,omitempty"`
+	EffectiveDate string `json:"effective_date,omitempty"`
+}
+
+// DTODiscount запрос на добавление скидки к подписке
+type DTODiscount struct {
+	Type      string `json:"type"`
+	Value     int    `json:"value"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date,omitempty"`
+	Code      string `json:"code,omitempty"`
+}
+
+// DTOReminder запрос на настройку напоминаний подписки
+type DTOReminder struct {
+	Channel  string `json:"channel"`
+	Target   string `json:"target,omitempty"`
+	LeadDays int    `json:"lead_days"`
 }
 
+// DTODigestSetting запрос на регулярную доставку дайджеста расходов
+type DTODigestSetting struct {
+	Period  string `json:"period"`
+	Channel string `json:"channel"`
+	Target  string `json:"target,omitempty"`
+}
+
+// DTOUsage событие использования подписки. Без used_at используется текущее время, без count — одно использование
+type DTOUsage struct {
+	UsedAt string `json:"used_at,omitempty"`
+	Count  int    `json:"count,omitempty"`
+}
+
+// DTOSimulation набор гипотетических изменений, стоимость которых считается на months месяцев вперед
+type DTOSimulation struct {
+	Months  int                 `json:"months,omitempty"`
+	Changes []DTOSimulateChange `json:"changes"`
+}
+
+// DTOSimulateChange одно гипотетическое изменение: cancel и change_price действуют на подписку
+// subscription_id с месяца from (по умолчанию текущего), add добавляет подписку subscription
+type DTOSimulateChange struct {
+	Action         string   `json:"action"`
+	SubscriptionID string   `json:"subscription_id,omitempty"`
+	From           string   `json:"from,omitempty"`
+	Price          int      `json:"price,omitempty"`
+	Subscription   *DTOSubs `json:"subscription,omitempty"`
+}
+
+// DTOPayment фактический платеж по подписке. Дата платежа в формате YYYY-MM-DD, валюта по умолчанию RUB
+type DTOPayment struct {
+	Amount    int    `json:"amount"`
+	Currency  string `json:"currency,omitempty"`
+	PaidAt    string `json:"paid_at"`
+	Method    string `json:"method,omitempty"`
+	Reference string `json:"reference,omitempty"`
+}
+
+// DTOConfirmProposals подписки из выписки, которые пользователь подтверждает
+type DTOConfirmProposals struct {
+	IDs []string `json:"ids"`
+}
+
+// DTOJournalAccounts счета для выгрузки проводок: расходный счет по умолчанию,
+// счет оплаты и расходные счета отдельных категорий
+type DTOJournalAccounts struct {
+	Default    string            `json:"default,omitempty"`
+	Funding    string            `json:"funding,omitempty"`
+	Categories map[string]string `json:"categories,omitempty"`
+}
+
+// SumResponse стоимость за период. TotalPrice совпадает с NetTotal — суммой к оплате с учетом скидок.
 type SumResponse struct {
 	TotalPrice int `json:"total_price"`
+	GrossTotal int `json:"gross_total"`
+	NetTotal   int `json:"net_total"`
 }
 
 func (d DTOSubs) Validate() error {
@@ -33,7 +178,423 @@ func (d DTOSubs) Validate() error {
 	if d.StartDate == "" {
 		return errStartDate
 	}
+	if d.HouseholdID != "" {
+		if _, err := uuid.Parse(d.HouseholdID); err != nil {
+			return errHouseholdUUID
+		}
+	}
+	if d.PlanID != "" {
+		if _, err := uuid.Parse(d.PlanID); err != nil {
+			return errPlanUUID
+		}
+	}
+	switch d.BillingCycle {
+	case "", "monthly", "quarterly", "yearly":
+	default:
+		return errBillingCycle
+	}
+	if err := d.validateTrial(); err != nil {
+		return err
+	}
+
+	return d.validateSplit()
+
+}
 
+// ValidateUpdate проверяет запрос на изменение подписки: те же правила, что и при создании,
+// кроме владельца и даты начала, которые при изменении не меняются
+func (d DTOSubs) ValidateUpdate() error {
+	if d.ServiceName == "" {
+		return errServiceName
+	}
+	if d.Price < 0 {
+		return errPriceNegative
+	}
+	if d.HouseholdID != "" {
+		if _, err := uuid.Parse(d.HouseholdID); err != nil {
+			return errHouseholdUUID
+		}
+	}
+	if d.PlanID != "" {
+		if _, err := uuid.Parse(d.PlanID); err != nil {
+			return errPlanUUID
+		}
+	}
+	switch d.BillingCycle {
+	case "", "monthly", "quarterly", "yearly":
+	default:
+		return errBillingCycle
+	}
+	return d.validateSplit()
+}
+
+// validateTrial проверяет параметры пробного периода
+func (d DTOSubs) validateTrial() error {
+	if d.TrialMonths < 0 {
+		return errTrialMonths
+	}
+	if d.TrialEndDate == "" {
+		return nil
+	}
+	if d.TrialMonths > 0 {
+		return errTrialBoth
+	}
+	trialEnd, err := time.Parse("01-2006", d.TrialEndDate)
+	if err != nil {
+		return errInvalidDate
+	}
+	if start, err := time.Parse("01-2006", d.StartDate); err == nil && trialEnd.Before(start) {
+		return errTrialBeforeStart
+	}
+	return nil
+}
+
+// validateSplit проверяет правила разделения стоимости между участниками
+func (d DTOSubs) validateSplit() error {
+	if len(d.Members) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(d.Members))
+	total := 0
+	for _, m := range d.Members {
+		if _, err := uuid.Parse(m.UserId); err != nil {
+			return errNoUUID
+		}
+		if seen[m.UserId] {
+			return errDuplicateMember
+		}
+		if m.Share < 0 {
+			return errShareNegative
+		}
+		seen[m.UserId] = true
+		total += m.Share
+	}
+
+	switch d.SplitType {
+	case "", "equal":
+	case "percentage":
+		if total != 100 {
+			return errPercentageTotal
+		}
+	case "fixed":
+		if total != d.Price {
+			return errFixedTotal
+		}
+	default:
+		return errSplitType
+	}
+	return nil
+}
+
+func (d DTOHousehold) Validate() error {
+	if d.Name == "" {
+		return errHouseholdName
+	}
+	for _, m := range d.Members {
+		if _, err := uuid.Parse(m); err != nil {
+			return errNoUUID
+		}
+	}
+	return nil
+}
+
+func (d DTOBudget) Validate() error {
+	if _, err := uuid.Parse(d.UserId); err != nil {
+		return errNoUUID
+	}
+	if d.MonthlyLimit <= 0 {
+		return errBudgetLimit
+	}
 	return nil
+}
+
+func (d DTOCatalogEntry) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return errServiceName
+	}
+	if d.DefaultPrice < 0 {
+		return errPriceNegative
+	}
+	return nil
+}
+
+func (d DTOPlan) Validate() error {
+	if strings.TrimSpace(d.Name) == "" {
+		return errPlanName
+	}
+	if d.Price < 0 {
+		return errPriceNegative
+	}
+	return nil
+}
 
+func (d DTOChangePlan) Validate() error {
+	if _, err := uuid.Parse(d.PlanID); err != nil {
+		return errPlanUUID
+	}
+	if _, err := time.Parse("01-2006", d.EffectiveDate); err != nil {
+		return errInvalidDate
+	}
+	return nil
+}
+
+func (d DTOPrice) Validate() error {
+	if d.Price < 0 {
+		return errPriceNegative
+	}
+	if _, err := time.Parse("01-2006", d.EffectiveFrom); err != nil {
+		return errInvalidDate
+	}
+	return nil
+}
+
+func (d DTOStatusChange) Validate() error {
+	if d.EffectiveDate == "" {
+		return nil
+	}
+	if _, err := time.Parse("01-2006", d.EffectiveDate); err != nil {
+		return errInvalidDate
+	}
+	return nil
+}
+
+func (d DTODiscount) Validate() error {
+	switch d.Type {
+	case "percentage":
+		if d.Value > 100 {
+			return errDiscountPercentage
+		}
+	case "fixed":
+	default:
+		return errDiscountType
+	}
+	if d.Value <= 0 {
+		return errDiscountValue
+	}
+	start, err := time.Parse("01-2006", d.StartDate)
+	if err != nil {
+		return errStartDate
+	}
+	if d.EndDate != "" {
+		end, err := time.Parse("01-2006", d.EndDate)
+		if err != nil {
+			return errInvalidDate
+		}
+		if end.Before(start) {
+			return errDiscountPeriod
+		}
+	}
+	return nil
+}
+
+func (d DTOReminder) Validate() error {
+	if err := validateChannel(d.Channel, d.Target); err != nil {
+		return err
+	}
+	if d.LeadDays < 0 || d.LeadDays > 365 {
+		return errReminderLeadDays
+	}
+	return nil
+}
+
+func (d DTOUsage) Validate() error {
+	if d.Count < 0 || d.Count > 10000 {
+		return errUsageCount
+	}
+	if d.UsedAt != "" {
+		usedAt, err := time.Parse(time.RFC3339, d.UsedAt)
+		if err != nil {
+			return errUsageTime
+		}
+		if usedAt.After(time.Now().Add(time.Hour)) {
+			return errUsageFuture
+		}
+	}
+	return nil
+}
+
+func (d DTOSimulation) Validate() error {
+	if d.Months < 0 || d.Months > 120 {
+		return errSimulationMonths
+	}
+	for _, c := range d.Changes {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d DTOSimulateChange) Validate() error {
+	switch d.Action {
+	case "cancel", "change_price":
+		if _, err := uuid.Parse(d.SubscriptionID); err != nil {
+			return errSimulationSubscription
+		}
+		if d.Action == "change_price" && d.Price < 0 {
+			return errPriceNegative
+		}
+		if d.From != "" {
+			if _, err := time.Parse("01-2006", d.From); err != nil {
+				return errInvalidDate
+			}
+		}
+		return nil
+	case "add":
+		if d.Subscription == nil {
+			return errSimulationAdd
+		}
+		return d.Subscription.Validate()
+	default:
+		return errSimulationAction
+	}
+}
+
+func (d DTOPayment) Validate() error {
+	if d.Amount <= 0 {
+		return errPaymentAmount
+	}
+	if d.Currency != "" && !isCurrencyCode(d.Currency) {
+		return errPaymentCurrency
+	}
+	if _, err := time.Parse(time.DateOnly, d.PaidAt); err != nil {
+		return errPaymentDate
+	}
+	// Метод и номер операции попадают в выгрузки, переводы строк в них недопустимы
+	if strings.ContainsFunc(d.Method, unicode.IsControl) || strings.ContainsFunc(d.Reference, unicode.IsControl) {
+		return errPaymentText
+	}
+	return nil
+}
+
+// isCurrencyCode проверяет трехбуквенный код валюты ISO 4217 в верхнем регистре
+func isCurrencyCode(s string) bool {
+	if len(s) != 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+func (d DTOConfirmProposals) Validate() error {
+	if len(d.IDs) == 0 {
+		return errProposalIDs
+	}
+	for _, id := range d.IDs {
+		if _, err := uuid.Parse(id); err != nil {
+			return errProposalIDs
+		}
+	}
+	return nil
+}
+
+func (d DTOJournalAccounts) Validate() error {
+	for _, account := range []string{d.Default, d.Funding} {
+		if account != "" && !isAccountName(account) {
+			return errJournalAccount
+		}
+	}
+	for category, account := range d.Categories {
+		if strings.TrimSpace(category) == "" || !isAccountName(account) {
+			return errJournalAccount
+		}
+	}
+	return nil
+}
+
+// accountTypes корневые счета, которые допускает beancount
+var accountTypes = []string{"Assets", "Liabilities", "Equity", "Income", "Expenses"}
+
+// isAccountName проверяет имя счета вида Expenses:Subscriptions:Video: первая часть — один из
+// accountTypes, остальные непустые, каждая с заглавной буквы, без пробелов и знаков
+func isAccountName(s string) bool {
+	parts := strings.Split(s, ":")
+	if !slices.Contains(accountTypes, parts[0]) {
+		return false
+	}
+	for _, part := range parts {
+		r := []rune(part)
+		if len(r) == 0 || !unicode.IsUpper(r[0]) {
+			return false
+		}
+		for _, c := range r {
+			if !unicode.IsLetter(c) && !unicode.IsDigit(c) && c != '-' {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ValidateJournal проверяет формат и источник выгрузки проводок
+func ValidateJournal(format, source string) error {
+	if format != "ledger" && format != "hledger" && format != "beancount" {
+		return errJournalFormat
+	}
+	if source != "expected" && source != "actual" {
+		return errJournalSource
+	}
+	return nil
+}
+
+func (d DTODigestSetting) Validate() error {
+	if err := ValidateDigestPeriod(d.Period); err != nil {
+		return err
+	}
+	return validateChannel(d.Channel, d.Target)
+}
+
+// ValidateDigestPeriod проверяет период дайджеста: week или month
+func ValidateDigestPeriod(period string) error {
+	if period != "week" && period != "month" {
+		return errDigestPeriod
+	}
+	return nil
+}
+
+// ValidateAggregate проверяет измерения группировки и метрики агрегации без повторов
+func ValidateAggregate(groupBy, metrics []string) error {
+	if !uniqueIn(groupBy, "service_name", "user_id", "category", "month") {
+		return errAggregateGroupBy
+	}
+	if len(metrics) == 0 || !uniqueIn(metrics, "sum", "count", "avg") {
+		return errAggregateMetric
+	}
+	return nil
+}
+
+// uniqueIn сообщает, что все значения допустимы и не повторяются
+func uniqueIn(values []string, allowed ...string) bool {
+	seen := make(map[string]bool, len(values))
+	for _, v := range values {
+		if seen[v] || !slices.Contains(allowed, v) {
+			return false
+		}
+		seen[v] = true
+	}
+	return true
+}
+
+// validateChannel проверяет канал доставки уведомлений и адрес для него
+func validateChannel(channel, target string) error {
+	switch channel {
+	case "email":
+		if _, err := mail.ParseAddress(target); err != nil {
+			return errChannelEmail
+		}
+	case "webhook":
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errChannelWebhook
+		}
+	case "log":
+	default:
+		return errChannel
+	}
+	return nil
 }
@@ -14,6 +14,60 @@ var (
 	errStartDate      = errors.New("start date is required")
 	errInvalidDate    = errors.New("invalid date format")
 	errNoUUID         = errors.New("user ID not UUID type")
+
+	errSplitType       = errors.New("split type must be one of: equal, percentage, fixed")
+	errShareNegative   = errors.New("member share cannot be negative")
+	errDuplicateMember = errors.New("member is listed more than once")
+	errPercentageTotal = errors.New("member percentages must add up to 100")
+	errFixedTotal      = errors.New("member fixed shares must add up to price")
+	errHouseholdName   = errors.New("household name is required")
+	errHouseholdUUID   = errors.New("household ID not UUID type")
+
+	errBudgetLimit = errors.New("monthly limit must be positive")
+
+	errPlanName = errors.New("plan name is required")
+	errPlanUUID = errors.New("plan ID not UUID type")
+
+	errTrialMonths      = errors.New("trial months cannot be negative")
+	errTrialBoth        = errors.New("specify either trial_months or trial_end_date")
+	errTrialBeforeStart = errors.New("trial end date is before start date")
+
+	errDiscountType       = errors.New("discount type must be one of: percentage, fixed")
+	errDiscountValue      = errors.New("discount value must be positive")
+	errDiscountPercentage = errors.New("discount percentage cannot exceed 100")
+	errDiscountPeriod     = errors.New("discount end date is before start date")
+
+	errChannel          = errors.New("channel must be one of: email, webhook, log")
+	errChannelEmail     = errors.New("target must be a valid email address for email channel")
+	errChannelWebhook   = errors.New("target must be an http(s) URL for webhook channel")
+	errReminderLeadDays = errors.New("reminder lead_days must be between 0 and 365")
+
+	errDigestPeriod = errors.New("digest period must be one of: week, month")
+
+	errBillingCycle = errors.New("billing cycle must be one of: monthly, quarterly, yearly")
+
+	errUsageCount  = errors.New("usage count must be between 0 and 10000")
+	errUsageTime   = errors.New("used_at must be an RFC 3339 timestamp")
+	errUsageFuture = errors.New("used_at must not be in the future")
+
+	errSimulationMonths       = errors.New("simulation months must be between 1 and 120")
+	errSimulationAction       = errors.New("change action must be one of: cancel, change_price, add")
+	errSimulationSubscription = errors.New("subscription_id must be a valid UUID for cancel and change_price")
+	errSimulationAdd          = errors.New("subscription is required for add")
+
+	errPaymentAmount   = errors.New("payment amount must be positive")
+	errPaymentCurrency = errors.New("currency must be a three-letter ISO 4217 code")
+	errPaymentDate     = errors.New("paid_at must be a date in YYYY-MM-DD format")
+	errPaymentText     = errors.New("method and reference must not contain control characters")
+
+	errProposalIDs = errors.New("ids must be a non-empty list of proposal UUIDs")
+
+	errJournalAccount = errors.New("account names must start with Assets, Liabilities, Equity, Income or Expenses followed by colon-separated parts starting with an uppercase letter, e.g. Expenses:Subscriptions")
+	errJournalFormat  = errors.New("format must be one of: ledger, hledger, beancount")
+	errJournalSource  = errors.New("source must be one of: expected, actual")
+
+	errAggregateGroupBy = errors.New("group_by must be a comma-separated list of: service_name, user_id, category, month")
+	errAggregateMetric  = errors.New("metric must be a comma-separated list of: sum, count, avg")
 )
 
 type ErrorResponse struct {
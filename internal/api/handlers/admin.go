@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+)
+
+// HandleListJobs godoc
+// @Summary      List background jobs
+// @Description  Фоновые задачи планировщика: расписание, результат последнего запуска и время следующего
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   model.JobRun
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /admin/jobs [get]
+func (h *HTTPHandlers) HandleListJobs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	jobs, err := h.subscriptionStore.Jobs(ctx)
+	if err != nil {
+		log.Printf("failed to list jobs: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, jobs); err != nil {
+		return
+	}
+	log.Printf("jobs get successfully: count=%d", len(jobs))
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+)
+
+// HandleAggregate godoc
+// @Summary      Aggregate subscription charges
+// @Description  Группировка ежемесячных списаний за период по любому набору измерений (service_name, user_id, category, month) с метриками sum, count и avg. count — число списаний (подписко-месяцев), avg — средний размер списания. Без group_by возвращается одна строка по всем списаниям
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id            query     string  false  "User ID"
+// @Param        service_name  query     string  false  "Service Name"
+// @Param        tag           query     string  false  "Tag"
+// @Param        from          query     string  true   "Start period (MM-YYYY)"
+// @Param        to            query     string  true   "End period (MM-YYYY)"
+// @Param        group_by      query     string  false  "Comma-separated dimensions: service_name, user_id, category, month"
+// @Param        metric        query     string  false  "Comma-separated metrics: sum, count, avg (default sum)"
+// @Success      200  {array}   model.AggregateRow
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/aggregate [get]
+func (h *HTTPHandlers) HandleAggregate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := readFilter(r, "id")
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	groupBy := readList(r, "group_by")
+	metrics := readList(r, "metric")
+	if len(metrics) == 0 {
+		metrics = []string{string(model.MetricSum)}
+	}
+	if err := datatransfer.ValidateAggregate(groupBy, metrics); err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dims := make([]model.Dimension, 0, len(groupBy))
+	for _, d := range groupBy {
+		dims = append(dims, model.Dimension(d))
+	}
+	ms := make([]model.Metric, 0, len(metrics))
+	for _, m := range metrics {
+		ms = append(ms, model.Metric(m))
+	}
+
+	rows, err := h.subscriptionStore.Aggregate(ctx, filter, from, to, dims, ms)
+	if err != nil {
+		log.Printf("failed to aggregate charges: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, rows); err != nil {
+		return
+	}
+	log.Printf("subscription charges aggregated successfully: group_by=%v rows=%d", groupBy, len(rows))
+}
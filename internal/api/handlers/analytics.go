@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+)
+
+// HandleAnalytics godoc
+// @Summary      Revenue and churn analytics
+// @Description  MRR и ARR, фактические списания (с учетом скидок), новые и завершившиеся подписки и отток по месяцам, средняя длительность подписок и самые дорогие сервисы за период
+// @Tags         admin
+// @Produce      json
+// @Param        from  query     string  true   "Start period (MM-YYYY)"
+// @Param        to    query     string  true   "End period (MM-YYYY)"
+// @Param        top   query     int     false  "Number of top services (default 5)"
+// @Success      200  {object}  model.Analytics
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /admin/analytics [get]
+func (h *HTTPHandlers) HandleAnalytics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if to.Before(from) {
+		datatransfer.WriteError(w, "'to' is before 'from'", http.StatusBadRequest)
+		return
+	}
+	top, err := readPositiveInt(r, "top", 5)
+	if err != nil {
+		datatransfer.WriteError(w, "top must be a positive integer", http.StatusBadRequest)
+		return
+	}
+
+	analytics, err := h.subscriptionStore.Analytics(ctx, from, to, top)
+	if err != nil {
+		log.Printf("failed to calculate analytics: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, analytics); err != nil {
+		return
+	}
+	log.Printf("analytics calculated successfully: from=%s to=%s mrr=%d",
+		from.Format("01-2006"), to.Format("01-2006"), analytics.MRR)
+}
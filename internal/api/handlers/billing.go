@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/report"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleBillingStatement godoc
+// @Summary      Printable PDF statement
+// @Description  Выписка пользователя за период в PDF для отчетов о расходах: каждая подписка, оплаченные месяцы, стоимость доли пользователя с учетом скидок и итог к оплате
+// @Tags         users
+// @Produce      application/pdf
+// @Param        id    path      string  true  "User ID"
+// @Param        from  query     string  true  "Start period (MM-YYYY)"
+// @Param        to    query     string  true  "End period (MM-YYYY)"
+// @Success      200  {file}    file
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/statement.pdf [get]
+func (h *HTTPHandlers) HandleBillingStatement(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from.After(to) {
+		datatransfer.WriteError(w, "'from' must not be after 'to'", http.StatusBadRequest)
+		return
+	}
+
+	statement, err := h.subscriptionStore.BillingStatement(ctx, userId, from, to)
+	if err != nil {
+		log.Printf("failed to build statement: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	pdf, err := report.BillingPDF(statement)
+	if err != nil {
+		log.Printf("failed to render statement: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="statement-%s-%s.pdf"`, from.Format("2006-01"), to.Format("2006-01")))
+	w.Write(pdf)
+	log.Printf("statement rendered successfully: user_id=%s lines=%d total=%d", userId, len(statement.Lines), statement.Total)
+}
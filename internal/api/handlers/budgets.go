@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleCreateBudget godoc
+// @Summary      Create budget
+// @Description  Создать месячный бюджет пользователя, при необходимости по конкретному сервису
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        budget  body      datatransfer.DTOBudget  true  "Budget"
+// @Success      201  {object}  model.Budget
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /budgets [post]
+func (h *HTTPHandlers) HandleCreateBudget(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var dto datatransfer.DTOBudget
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("budget bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.subscriptionStore.CreateBudget(ctx, dto)
+	if err != nil {
+		log.Printf("failed to create budget: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, budget); err != nil {
+		return
+	}
+	log.Printf("budget add successfully: id=%s", budget.ID)
+}
+
+// HandleGetAllBudgets godoc
+// @Summary      Get budgets
+// @Description  Получить список бюджетов, при необходимости только для одного пользователя
+// @Tags         budgets
+// @Produce      json
+// @Param        user_id  query     string  false  "User ID"
+// @Success      200  {array}   model.Budget
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /budgets [get]
+func (h *HTTPHandlers) HandleGetAllBudgets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	budgets, err := h.subscriptionStore.ListBudgets(ctx, r.URL.Query().Get("user_id"))
+	if err != nil {
+		log.Printf("failed to get budgets: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, budgets); err != nil {
+		return
+	}
+	log.Printf("budgets get successfully")
+}
+
+// HandleGetBudget godoc
+// @Summary      Get budget
+// @Description  Получить бюджет по ID
+// @Tags         budgets
+// @Produce      json
+// @Param        id   path      string  true  "Budget ID"
+// @Success      200  {object}  model.Budget
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /budgets/{id} [get]
+func (h *HTTPHandlers) HandleGetBudget(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	budget, err := h.subscriptionStore.GetBudget(ctx, id)
+	if err != nil {
+		writeStoreError(w, "budget", id, err)
+		return
+	}
+
+	if err := writeJSON(w, budget); err != nil {
+		return
+	}
+	log.Printf("budget retrieved successfully: id=%s", id)
+}
+
+// HandleUpdateBudget godoc
+// @Summary      Update budget
+// @Description  Изменить сервис или лимит бюджета
+// @Tags         budgets
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                  true  "Budget ID"
+// @Param        budget  body      datatransfer.DTOBudget  true  "Budget"
+// @Success      200  {object}  model.Budget
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /budgets/{id} [put]
+func (h *HTTPHandlers) HandleUpdateBudget(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOBudget
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("budget bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if dto.MonthlyLimit <= 0 {
+		datatransfer.WriteError(w, "monthly limit must be positive", http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.subscriptionStore.UpdateBudget(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "budget", id, err)
+		return
+	}
+
+	if err := writeJSON(w, budget); err != nil {
+		return
+	}
+	log.Printf("budget update successfully: id=%s", id)
+}
+
+// HandleDeleteBudget godoc
+// @Summary      Delete budget
+// @Description  Удалить бюджет
+// @Tags         budgets
+// @Produce      json
+// @Param        id   path      string  true  "Budget ID"
+// @Success      204  "No Content"
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /budgets/{id} [delete]
+func (h *HTTPHandlers) HandleDeleteBudget(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	if err := h.subscriptionStore.DeleteBudget(ctx, id); err != nil {
+		log.Printf("internal server error: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleBudgetStatus godoc
+// @Summary      Budget status
+// @Description  Использование бюджета в текущем месяце
+// @Tags         budgets
+// @Produce      json
+// @Param        id   path      string  true  "Budget ID"
+// @Success      200  {object}  model.BudgetStatus
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /budgets/{id}/status [get]
+func (h *HTTPHandlers) HandleBudgetStatus(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	status, err := h.subscriptionStore.BudgetStatus(ctx, id)
+	if err != nil {
+		writeStoreError(w, "budget", id, err)
+		return
+	}
+
+	if err := writeJSON(w, status); err != nil {
+		return
+	}
+	log.Printf("budget status calculated successfully: id=%s spent=%d", id, status.Spent)
+}
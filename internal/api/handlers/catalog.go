@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleCreateCatalogEntry godoc
+// @Summary      Create catalog entry
+// @Description  Добавить сервис в справочник: каноническое название, синонимы, цена по умолчанию, категория, сайт. Существующие подписки с подходящими названиями привязываются к новой записи
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        entry  body      datatransfer.DTOCatalogEntry  true  "Catalog entry"
+// @Success      201  {object}  model.CatalogEntry
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog [post]
+func (h *HTTPHandlers) HandleCreateCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var dto datatransfer.DTOCatalogEntry
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("catalog bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.subscriptionStore.CreateCatalogEntry(ctx, dto)
+	if err != nil {
+		log.Printf("failed to create catalog entry: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, entry); err != nil {
+		return
+	}
+	log.Printf("catalog entry add successfully: id=%s", entry.ID)
+}
+
+// HandleGetCatalog godoc
+// @Summary      Get catalog
+// @Description  Получить справочник сервисов
+// @Tags         catalog
+// @Produce      json
+// @Success      200  {array}   model.CatalogEntry
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog [get]
+func (h *HTTPHandlers) HandleGetCatalog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	catalog, err := h.subscriptionStore.ListCatalog(ctx)
+	if err != nil {
+		log.Printf("failed to get catalog: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, catalog); err != nil {
+		return
+	}
+	log.Printf("catalog get successfully")
+}
+
+// HandleGetCatalogEntry godoc
+// @Summary      Get catalog entry
+// @Description  Получить запись справочника сервисов по ID
+// @Tags         catalog
+// @Produce      json
+// @Param        id   path      string  true  "Catalog entry ID"
+// @Success      200  {object}  model.CatalogEntry
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog/{id} [get]
+func (h *HTTPHandlers) HandleGetCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	entry, err := h.subscriptionStore.GetCatalogEntry(ctx, id)
+	if err != nil {
+		writeStoreError(w, "catalog entry", id, err)
+		return
+	}
+
+	if err := writeJSON(w, entry); err != nil {
+		return
+	}
+	log.Printf("catalog entry retrieved successfully: id=%s", id)
+}
+
+// HandleUpdateCatalogEntry godoc
+// @Summary      Update catalog entry
+// @Description  Изменить запись справочника сервисов. Привязанные подписки получают новое название, подписки с подходящими новыми синонимами привязываются к записи
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                        true  "Catalog entry ID"
+// @Param        entry  body      datatransfer.DTOCatalogEntry  true  "Catalog entry"
+// @Success      200  {object}  model.CatalogEntry
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog/{id} [put]
+func (h *HTTPHandlers) HandleUpdateCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOCatalogEntry
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("catalog bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.subscriptionStore.UpdateCatalogEntry(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "catalog entry", id, err)
+		return
+	}
+
+	if err := writeJSON(w, entry); err != nil {
+		return
+	}
+	log.Printf("catalog entry update successfully: id=%s", id)
+}
+
+// HandleDeleteCatalogEntry godoc
+// @Summary      Delete catalog entry
+// @Description  Удалить запись справочника сервисов, подписки теряют ссылку на неё
+// @Tags         catalog
+// @Produce      json
+// @Param        id   path      string  true  "Catalog entry ID"
+// @Success      204  "No Content"
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog/{id} [delete]
+func (h *HTTPHandlers) HandleDeleteCatalogEntry(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	if err := h.subscriptionStore.DeleteCatalogEntry(ctx, id); err != nil {
+		log.Printf("internal server error: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleResolveService godoc
+// @Summary      Resolve service name
+// @Description  Найти запись справочника по свободному названию сервиса с учетом синонимов и опечаток
+// @Tags         catalog
+// @Produce      json
+// @Param        name  query     string  true  "Service Name"
+// @Success      200  {object}  model.CatalogEntry
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog/resolve [get]
+func (h *HTTPHandlers) HandleResolveService(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		datatransfer.WriteError(w, "missing 'name' query parameter", http.StatusBadRequest)
+		return
+	}
+
+	entry, ok, err := h.subscriptionStore.ResolveService(ctx, name)
+	if err != nil {
+		log.Printf("failed to resolve service: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		datatransfer.WriteError(w, "service not found in catalog", http.StatusNotFound)
+		return
+	}
+
+	if err := writeJSON(w, entry); err != nil {
+		return
+	}
+	log.Printf("service resolved successfully: name=%s catalog=%s", name, entry.Name)
+}
@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"time"
+
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"subscription/internal/report"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleGetDigest godoc
+// @Summary      Get spending digest
+// @Description  Сводка расходов пользователя за текущую неделю или месяц в сравнении с предыдущим периодом: действующие, новые и завершенные подписки и ближайшие списания. format выбирает JSON, HTML или текст
+// @Tags         users
+// @Produce      json
+// @Produce      html
+// @Produce      plain
+// @Param        id      path      string  true   "User ID"
+// @Param        period  query     string  false  "Period: week or month (default month)"
+// @Param        format  query     string  false  "Format: json, html or text (default json)"
+// @Success      200  {object}  model.Digest
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/digest [get]
+func (h *HTTPHandlers) HandleGetDigest(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	period := r.URL.Query().Get("period")
+	if period == "" {
+		period = string(model.DigestMonth)
+	}
+	if err := datatransfer.ValidateDigestPeriod(period); err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format != "" && format != "json" && format != "html" && format != "text" {
+		datatransfer.WriteError(w, "format must be one of: json, html, text", http.StatusBadRequest)
+		return
+	}
+
+	digest, err := h.subscriptionStore.Digest(ctx, userId, model.DigestPeriod(period), time.Now())
+	if err != nil {
+		writeStoreError(w, "user", userId, err)
+		return
+	}
+
+	switch format {
+	case "html":
+		body, err := report.DigestHTML(digest)
+		if err != nil {
+			log.Printf("failed to render digest: %v", err)
+			datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(body))
+	case "text":
+		body, err := report.DigestText(digest)
+		if err != nil {
+			log.Printf("failed to render digest: %v", err)
+			datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Write([]byte(body))
+	default:
+		if err := writeJSON(w, digest); err != nil {
+			return
+		}
+	}
+	log.Printf("digest built successfully: user_id=%s period=%s total=%d", userId, period, digest.Total)
+}
+
+// HandleAddDigestSetting godoc
+// @Summary      Subscribe to spending digest
+// @Description  Включить регулярную доставку дайджеста за прошедшую неделю или месяц по каналу email, webhook или log
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                         true  "User ID"
+// @Param        digest  body      datatransfer.DTODigestSetting  true  "Digest setting"
+// @Success      201  {object}  model.DigestSetting
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/digests [post]
+func (h *HTTPHandlers) HandleAddDigestSetting(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTODigestSetting
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("digest setting bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	setting, err := h.subscriptionStore.AddDigestSetting(ctx, userId, dto)
+	if err != nil {
+		writeStoreError(w, "user", userId, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, setting); err != nil {
+		return
+	}
+	log.Printf("digest setting add successfully: user_id=%s id=%s period=%s", userId, setting.ID, setting.Period)
+}
+
+// HandleGetDigestSettings godoc
+// @Summary      Get digest settings
+// @Description  Настройки доставки дайджестов пользователя
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {array}   model.DigestSetting
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/digests [get]
+func (h *HTTPHandlers) HandleGetDigestSettings(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	settings, err := h.subscriptionStore.ListDigestSettings(ctx, userId)
+	if err != nil {
+		writeStoreError(w, "user", userId, err)
+		return
+	}
+
+	if err := writeJSON(w, settings); err != nil {
+		return
+	}
+	log.Printf("digest settings get successfully: user_id=%s", userId)
+}
+
+// HandleDeleteDigestSetting godoc
+// @Summary      Unsubscribe from spending digest
+// @Description  Удалить настройку доставки дайджеста
+// @Tags         users
+// @Produce      json
+// @Param        id         path      string  true  "User ID"
+// @Param        digest_id  path      string  true  "Digest setting ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/digests/{digest_id} [delete]
+func (h *HTTPHandlers) HandleDeleteDigestSetting(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	digestID := chi.URLParam(r, "digest_id")
+	ctx := r.Context()
+
+	if err := h.subscriptionStore.DeleteDigestSetting(ctx, userId, digestID); err != nil {
+		writeStoreError(w, "digest setting", digestID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	log.Printf("digest setting delete successfully: user_id=%s id=%s", userId, digestID)
+}
@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAddDiscount godoc
+// @Summary      Add subscription discount
+// @Description  Добавить скидку (процент или фиксированная сумма) на период MM-YYYY. Без end_date скидка действует бессрочно
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                    true  "Subscription ID"
+// @Param        discount  body      datatransfer.DTODiscount  true  "Discount"
+// @Success      201  {object}  model.Discount
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/discounts [post]
+func (h *HTTPHandlers) HandleAddDiscount(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTODiscount
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("discount bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	discount, err := h.subscriptionStore.AddDiscount(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, discount); err != nil {
+		return
+	}
+	log.Printf("subscription discount add successfully: id=%s discount_id=%s", id, discount.ID)
+}
+
+// HandleGetDiscounts godoc
+// @Summary      Get subscription discounts
+// @Description  Список скидок подписки
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {array}   model.Discount
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/discounts [get]
+func (h *HTTPHandlers) HandleGetDiscounts(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	discounts, err := h.subscriptionStore.ListDiscounts(ctx, id)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, discounts); err != nil {
+		return
+	}
+	log.Printf("subscription discounts get successfully: id=%s", id)
+}
+
+// HandleDeleteDiscount godoc
+// @Summary      Delete subscription discount
+// @Description  Удалить скидку подписки
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id           path      string  true  "Subscription ID"
+// @Param        discount_id  path      string  true  "Discount ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/discounts/{discount_id} [delete]
+func (h *HTTPHandlers) HandleDeleteDiscount(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	discountID := chi.URLParam(r, "discount_id")
+	ctx := r.Context()
+
+	if err := h.subscriptionStore.DeleteDiscount(ctx, id, discountID); err != nil {
+		writeStoreError(w, "discount", discountID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	log.Printf("subscription discount delete successfully: id=%s discount_id=%s", id, discountID)
+}
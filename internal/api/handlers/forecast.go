@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+)
+
+// maxForecastMonths ограничивает горизонт прогноза десятью годами
+const maxForecastMonths = 120
+
+// HandleForecast godoc
+// @Summary      Forecast subscription spending
+// @Description  Прогноз ежемесячных и накопленных расходов на months месяцев вперед, начиная с текущего. Учитывает периодичность оплаты, запланированные цены, скидки, пробные периоды и даты окончания
+// @Tags         subscriptions
+// @Produce      json
+// @Param        months   query     int     false  "Forecast horizon in months (default 12, max 120)"
+// @Param        user_id  query     string  false  "User ID"
+// @Success      200  {object}  model.Forecast
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/forecast [get]
+func (h *HTTPHandlers) HandleForecast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := r.URL.Query().Get("user_id")
+
+	months, err := readPositiveInt(r, "months", 12)
+	if err != nil || months > maxForecastMonths {
+		datatransfer.WriteError(w, "months must be a positive integer not greater than 120", http.StatusBadRequest)
+		return
+	}
+
+	forecast, err := h.subscriptionStore.Forecast(ctx, userId, months)
+	if err != nil {
+		log.Printf("failed to build forecast: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, forecast); err != nil {
+		return
+	}
+	log.Printf("forecast built successfully: user_id=%s months=%d total=%d", userId, months, forecast.Total.Net)
+}
@@ -18,10 +18,87 @@ import (
 type ServiceRepository interface {
 	Create(ctx context.Context, dto datatransfer.DTOSubs) (model.Subscription, error)
 	GetInfo(ctx context.Context, idSub string) (model.Subscription, error)
-	GetAll(ctx context.Context) ([]model.Subscription, error)
+	GetAll(ctx context.Context, filter model.Filter) ([]model.Subscription, error)
 	Delete(ctx context.Context, idSub string) error
 	Update(ctx context.Context, id string, dto datatransfer.DTOSubs) (model.Subscription, error)
-	Sum(ctx context.Context, userId, serviceName string, from, to time.Time) (int, error)
+	Sum(ctx context.Context, filter model.Filter, from, to time.Time) (model.Totals, error)
+	SumByCategory(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.CategoryTotal, error)
+	Compare(ctx context.Context, filter model.Filter, from, to, prevFrom, prevTo time.Time) (model.Comparison, error)
+	Aggregate(ctx context.Context, filter model.Filter, from, to time.Time, groupBy []model.Dimension, metrics []model.Metric) ([]model.AggregateRow, error)
+
+	CreateHousehold(ctx context.Context, dto datatransfer.DTOHousehold) (model.Household, error)
+	GetHousehold(ctx context.Context, id string) (model.Household, error)
+	Balances(ctx context.Context, householdID string, from, to time.Time) (model.Balances, error)
+
+	CreateBudget(ctx context.Context, dto datatransfer.DTOBudget) (model.Budget, error)
+	GetBudget(ctx context.Context, id string) (model.Budget, error)
+	ListBudgets(ctx context.Context, userId string) ([]model.Budget, error)
+	UpdateBudget(ctx context.Context, id string, dto datatransfer.DTOBudget) (model.Budget, error)
+	DeleteBudget(ctx context.Context, id string) error
+	BudgetStatus(ctx context.Context, id string) (model.BudgetStatus, error)
+
+	CreateCatalogEntry(ctx context.Context, dto datatransfer.DTOCatalogEntry) (model.CatalogEntry, error)
+	GetCatalogEntry(ctx context.Context, id string) (model.CatalogEntry, error)
+	ListCatalog(ctx context.Context) ([]model.CatalogEntry, error)
+	UpdateCatalogEntry(ctx context.Context, id string, dto datatransfer.DTOCatalogEntry) (model.CatalogEntry, error)
+	DeleteCatalogEntry(ctx context.Context, id string) error
+	ResolveService(ctx context.Context, name string) (model.CatalogEntry, bool, error)
+
+	CreatePlan(ctx context.Context, catalogID string, dto datatransfer.DTOPlan) (model.Plan, error)
+	ListPlans(ctx context.Context, catalogID string) ([]model.Plan, error)
+	ChangePlan(ctx context.Context, id string, dto datatransfer.DTOChangePlan) (model.Subscription, error)
+	ListPlanChanges(ctx context.Context, id string) ([]model.PlanChange, error)
+
+	AddPrice(ctx context.Context, id string, dto datatransfer.DTOPrice) ([]model.PricePoint, error)
+	ListPrices(ctx context.Context, id string) ([]model.PricePoint, error)
+
+	AddDiscount(ctx context.Context, id string, dto datatransfer.DTODiscount) (model.Discount, error)
+	ListDiscounts(ctx context.Context, id string) ([]model.Discount, error)
+	DeleteDiscount(ctx context.Context, id, discountID string) error
+
+	AddReminder(ctx context.Context, id string, dto datatransfer.DTOReminder) (model.ReminderSetting, error)
+	ListReminders(ctx context.Context, id string) ([]model.ReminderSetting, error)
+	DeleteReminder(ctx context.Context, id, reminderID string) error
+
+	Pause(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error)
+	Resume(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error)
+	Cancel(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error)
+	StatusHistory(ctx context.Context, id string) ([]model.StatusChange, error)
+
+	TrialsEnding(ctx context.Context, userId string, days int) ([]model.TrialEnding, error)
+
+	Jobs(ctx context.Context) ([]model.JobRun, error)
+	Analytics(ctx context.Context, from, to time.Time, top int) (model.Analytics, error)
+
+	Forecast(ctx context.Context, userId string, months int) (model.Forecast, error)
+
+	Digest(ctx context.Context, userId string, period model.DigestPeriod, at time.Time) (model.Digest, error)
+	AddDigestSetting(ctx context.Context, userId string, dto datatransfer.DTODigestSetting) (model.DigestSetting, error)
+	ListDigestSettings(ctx context.Context, userId string) ([]model.DigestSetting, error)
+	DeleteDigestSetting(ctx context.Context, userId, id string) error
+
+	Insights(ctx context.Context, userId string) (model.Insights, error)
+	Simulate(ctx context.Context, userId string, dto datatransfer.DTOSimulation) (model.Simulation, error)
+
+	RecordUsage(ctx context.Context, id string, dto datatransfer.DTOUsage) (model.Usage, error)
+	Unused(ctx context.Context, userId string, days int) ([]model.UnusedSubscription, error)
+
+	AddPayment(ctx context.Context, id string, dto datatransfer.DTOPayment) (model.Payment, error)
+	GetPayment(ctx context.Context, id, paymentID string) (model.Payment, error)
+	ListPayments(ctx context.Context, id string) ([]model.Payment, error)
+	UpdatePayment(ctx context.Context, id, paymentID string, dto datatransfer.DTOPayment) (model.Payment, error)
+	DeletePayment(ctx context.Context, id, paymentID string) error
+	Reconcile(ctx context.Context, filter model.Filter, from, to time.Time) (model.Reconciliation, error)
+
+	ImportStatement(ctx context.Context, userId string, txns []model.Transaction) ([]model.Proposal, error)
+	ListProposals(ctx context.Context, userId string) ([]model.Proposal, error)
+	ConfirmProposals(ctx context.Context, userId string, dto datatransfer.DTOConfirmProposals) ([]model.Subscription, error)
+
+	Journal(ctx context.Context, userId string, from, to time.Time, format model.JournalFormat, source model.JournalSource) (model.Journal, error)
+	JournalAccounts(ctx context.Context, userId string) (model.JournalAccounts, error)
+	SetJournalAccounts(ctx context.Context, userId string, dto datatransfer.DTOJournalAccounts) (model.JournalAccounts, error)
+
+	BillingStatement(ctx context.Context, userId string, from, to time.Time) (model.BillingStatement, error)
 }
 
 type HTTPHandlers struct {
@@ -113,16 +190,24 @@ func (h *HTTPHandlers) HandleGetInfoSubscribe(w http.ResponseWriter, r *http.Req
 
 // HandleGetAllSubscriptions godoc
 // @Summary      Get all subscriptions
-// @Description  Получить список всех подписок
+// @Description  Получить список всех подписок с фильтрацией. Истекшие подписки возвращаются только с include_expired=true
 // @Tags         subscriptions
 // @Produce      json
+// @Param        user_id          query     string  false  "User ID"
+// @Param        service_name     query     string  false  "Service Name"
+// @Param        category         query     string  false  "Category"
+// @Param        tag              query     string  false  "Tag"
+// @Param        include_expired  query     bool    false  "Include expired subscriptions"
 // @Success      200  {array}   model.Subscription
 // @Failure      500  {object}  datatransfer.ErrorResponse
 // @Router       /subscriptions [get]
 func (h *HTTPHandlers) HandleGetAllInfoSubscribe(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	subs, err := h.subscriptionStore.GetAll(ctx)
+	filter := readFilter(r, "user_id")
+	filter.IncludeExpired = r.URL.Query().Get("include_expired") == "true"
+
+	subs, err := h.subscriptionStore.GetAll(ctx, filter)
 	if err != nil {
 		log.Printf("failed to get subs info: %v", err)
 		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
@@ -161,7 +246,7 @@ func (h *HTTPHandlers) HandleDeleteSubscribe(w http.ResponseWriter, r *http.Requ
 
 // HandleUpdateSubscription godoc
 // @Summary      Update subscription
-// @Description  Обновить информацию о подписке
+// @Description  Обновить информацию о подписке. Новая цена действует с текущего месяца, прошлые списания не меняются. Не указанные household_id, category, tags и billing_cycle остаются прежними, пустой список tags очищает теги. split_type и members заменяются вместе: если указано хотя бы одно из них, разделение берется из запроса, иначе остается прежним
 // @Tags         subscriptions
 // @Accept       json
 // @Produce      json
@@ -184,10 +269,15 @@ func (h *HTTPHandlers) HandleUpdateSubscribe(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if err := dto.ValidateUpdate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
 	updatedSub, err := h.subscriptionStore.Update(ctx, userId, dto)
 	if err != nil {
-		log.Printf("failed to update subscription: %v", err)
-		datatransfer.WriteError(w, "failed to update subscription", http.StatusInternalServerError)
+		writeStoreError(w, "subscription", userId, err)
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -202,11 +292,13 @@ func (h *HTTPHandlers) HandleUpdateSubscribe(w http.ResponseWriter, r *http.Requ
 
 // HandleSumInfo godoc
 // @Summary      Calculate total subscription cost
-// @Description  Подсчёт суммарной стоимости всех подписок за период с фильтрацией
+// @Description  Подсчёт суммарной стоимости всех подписок за период с фильтрацией, до и после скидок
 // @Tags         subscriptions
 // @Produce      json
 // @Param        id            query     string  false  "User ID"
 // @Param        service_name  query     string  false  "Service Name"
+// @Param        category      query     string  false  "Category"
+// @Param        tag           query     string  false  "Tag"
 // @Param        from          query     string  true   "Start period (MM-YYYY)"
 // @Param        to            query     string  true   "End period (MM-YYYY)"
 // @Success      200  {object}  datatransfer.SumResponse
@@ -216,8 +308,7 @@ func (h *HTTPHandlers) HandleUpdateSubscribe(w http.ResponseWriter, r *http.Requ
 func (h *HTTPHandlers) HandleSumInfo(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	userID := r.URL.Query().Get("id")
-	serviceName := r.URL.Query().Get("service_name")
+	filter := readFilter(r, "id")
 	fromStr := r.URL.Query().Get("from")
 	toStr := r.URL.Query().Get("to")
 
@@ -239,19 +330,110 @@ func (h *HTTPHandlers) HandleSumInfo(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Получаем сумму
-	sum, err := h.subscriptionStore.Sum(ctx, userID, serviceName, fromDate.Time, toDate.Time)
+	sum, err := h.subscriptionStore.Sum(ctx, filter, fromDate.Time, toDate.Time)
 	if err != nil {
 		log.Printf("failed to calculate sum: %v", err)
 		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
 		return
 	}
 
-	resp := datatransfer.SumResponse{TotalPrice: sum}
+	resp := datatransfer.SumResponse{TotalPrice: sum.Net, GrossTotal: sum.Gross, NetTotal: sum.Net}
 
 	if err := writeJSON(w, resp); err != nil {
 		return
 	}
 
-	log.Printf("subscription sum calculated successfully: user_id=%s service_name=%s from=%s to=%s sum=%d",
-		userID, serviceName, fromStr, toStr, sum)
+	log.Printf("subscription sum calculated successfully: user_id=%s service_name=%s from=%s to=%s gross=%d net=%d",
+		filter.UserId, filter.ServiceName, fromStr, toStr, sum.Gross, sum.Net)
+}
+
+// HandleSumByCategory godoc
+// @Summary      Calculate subscription cost by category
+// @Description  Подсчёт стоимости подписок за период с группировкой по категориям
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id            query     string  false  "User ID"
+// @Param        service_name  query     string  false  "Service Name"
+// @Param        category      query     string  false  "Category"
+// @Param        tag           query     string  false  "Tag"
+// @Param        from          query     string  true   "Start period (MM-YYYY)"
+// @Param        to            query     string  true   "End period (MM-YYYY)"
+// @Success      200  {array}   model.CategoryTotal
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/sum/by-category [get]
+func (h *HTTPHandlers) HandleSumByCategory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := readFilter(r, "id")
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	totals, err := h.subscriptionStore.SumByCategory(ctx, filter, from, to)
+	if err != nil {
+		log.Printf("failed to calculate sum by category: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, totals); err != nil {
+		return
+	}
+
+	log.Printf("subscription sum by category calculated successfully: user_id=%s", filter.UserId)
+}
+
+// HandleSumCompare godoc
+// @Summary      Compare subscription cost with another period
+// @Description  Сравнение стоимости подписок за период с другим периодом: предыдущим периодом той же длины (compare=previous), тем же периодом годом ранее (compare=year) или произвольным (compare_from, compare_to). Возвращает обе суммы, абсолютное и процентное изменение и вклад каждого сервиса
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id            query     string  false  "User ID"
+// @Param        service_name  query     string  false  "Service Name"
+// @Param        category      query     string  false  "Category"
+// @Param        tag           query     string  false  "Tag"
+// @Param        from          query     string  true   "Start period (MM-YYYY)"
+// @Param        to            query     string  true   "End period (MM-YYYY)"
+// @Param        compare       query     string  false  "Comparison window: previous or year (default previous)"
+// @Param        compare_from  query     string  false  "Custom comparison start (MM-YYYY)"
+// @Param        compare_to    query     string  false  "Custom comparison end (MM-YYYY)"
+// @Success      200  {object}  model.Comparison
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/sum/compare [get]
+func (h *HTTPHandlers) HandleSumCompare(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := readFilter(r, "id")
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from.After(to) {
+		datatransfer.WriteError(w, "'from' must not be after 'to'", http.StatusBadRequest)
+		return
+	}
+
+	prevFrom, prevTo, err := readCompareWindow(r, from, to)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	comparison, err := h.subscriptionStore.Compare(ctx, filter, from, to, prevFrom, prevTo)
+	if err != nil {
+		log.Printf("failed to compare sums: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, comparison); err != nil {
+		return
+	}
+
+	log.Printf("subscription sum compared successfully: user_id=%s delta=%d", filter.UserId, comparison.Delta)
 }
@@ -3,7 +3,9 @@ package handlers_test
 import (
 	"bytes"
 	"context"
+	"database/sql"
 	"encoding/json"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	datatransfer "subscription/internal/api/dto"
@@ -11,9 +13,13 @@ import (
 	"subscription/internal/model"
 	"testing"
 	"time"
+
+	"github.com/go-chi/chi/v5"
 )
 
-type fakeService struct{}
+type fakeService struct {
+	handlers.ServiceRepository
+}
 
 func (f *fakeService) Create(ctx context.Context, dto datatransfer.DTOSubs) (model.Subscription, error) {
 	return model.Subscription{}, nil
@@ -21,7 +27,7 @@ func (f *fakeService) Create(ctx context.Context, dto datatransfer.DTOSubs) (mod
 func (f *fakeService) GetInfo(ctx context.Context, idSub string) (model.Subscription, error) {
 	return model.Subscription{}, nil
 }
-func (f *fakeService) GetAll(ctx context.Context) ([]model.Subscription, error) {
+func (f *fakeService) GetAll(ctx context.Context, filter model.Filter) ([]model.Subscription, error) {
 	return []model.Subscription{}, nil
 }
 func (f *fakeService) Delete(ctx context.Context, idSub string) error {
@@ -30,18 +36,66 @@ func (f *fakeService) Delete(ctx context.Context, idSub string) error {
 func (f *fakeService) Update(ctx context.Context, id string, dto datatransfer.DTOSubs) (model.Subscription, error) {
 	return model.Subscription{}, nil
 }
-func (f *fakeService) Sum(ctx context.Context, userId, serviceName string, from, to time.Time) (int, error) {
-	return 1, nil
+func (f *fakeService) Sum(ctx context.Context, filter model.Filter, from, to time.Time) (model.Totals, error) {
+	return model.Totals{Gross: 1, Net: 1}, nil
+}
+func (f *fakeService) Aggregate(ctx context.Context, filter model.Filter, from, to time.Time, groupBy []model.Dimension, metrics []model.Metric) ([]model.AggregateRow, error) {
+	return []model.AggregateRow{}, nil
+}
+func (f *fakeService) ImportStatement(ctx context.Context, userId string, txns []model.Transaction) ([]model.Proposal, error) {
+	proposals := make([]model.Proposal, len(txns))
+	return proposals, nil
+}
+func (f *fakeService) BillingStatement(ctx context.Context, userId string, from, to time.Time) (model.BillingStatement, error) {
+	return model.BillingStatement{
+		UserId:   userId,
+		From:     from,
+		To:       to,
+		Currency: model.BaseCurrency,
+		Lines: []model.BillingLine{
+			{ServiceName: "Яндекс Плюс", Months: []time.Time{from, from.AddDate(0, 1, 0)}, Amount: 598},
+		},
+		Total: 598,
+	}, nil
+}
+func (f *fakeService) Simulate(ctx context.Context, userId string, dto datatransfer.DTOSimulation) (model.Simulation, error) {
+	return model.Simulation{}, nil
+}
+func (f *fakeService) SetJournalAccounts(ctx context.Context, userId string, dto datatransfer.DTOJournalAccounts) (model.JournalAccounts, error) {
+	return model.NewJournalAccounts(userId, dto), nil
+}
+func (f *fakeService) AddPayment(ctx context.Context, id string, dto datatransfer.DTOPayment) (model.Payment, error) {
+	return model.NewPayment(id, dto)
+}
+func (f *fakeService) Analytics(ctx context.Context, from, to time.Time, top int) (model.Analytics, error) {
+	return model.Analytics{MRR: 100, ARR: 1200}, nil
+}
+func (f *fakeService) SumByCategory(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.CategoryTotal, error) {
+	return []model.CategoryTotal{{Category: filter.Category, Total: 1}}, nil
+}
+
+func (f *fakeService) CreateHousehold(ctx context.Context, dto datatransfer.DTOHousehold) (model.Household, error) {
+	return model.Household{}, nil
+}
+func (f *fakeService) Balances(ctx context.Context, householdID string, from, to time.Time) (model.Balances, error) {
+	return model.Balances{}, nil
+}
+
+func (f *fakeService) BudgetStatus(ctx context.Context, id string) (model.BudgetStatus, error) {
+	if id == "missing" {
+		return model.BudgetStatus{}, sql.ErrNoRows
+	}
+	return model.BudgetStatus{}, nil
 }
 
 func TestHandleSubscribe_Unit(t *testing.T) {
 
 	h := handlers.NewHTTPHandlers(&fakeService{})
 	dto := datatransfer.DTOSubs{
-		UserId:      "user1",
+		UserId:      "a37a0327-99af-4e62-8b33-55dc3863cdc6",
 		ServiceName: "Netflix",
 		Price:       10,
-		StartDate:   "26.10.2025",
+		StartDate:   "10-2025",
 	}
 	body, _ := json.Marshal(dto)
 
@@ -107,4 +161,285 @@ func TestHandleDelete_Unit(t *testing.T) {
 
 func TestHandleUpdateSubscribe_Unit(t *testing.T) {
 
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	for body, status := range map[string]int{
+		// Тело без полей разделения допустимо: прежние участники сохраняются
+		`{"service_name": "Netflix", "price": 300}`: http.StatusOK,
+		`{"service_name": "Netflix", "price": 300, "split_type": "percentage", "members": [{"user_id": "` + partner + `", "share": 100}]}`: http.StatusOK,
+		`{"service_name": "Netflix", "price": 300, "split_type": "fixed", "members": [{"user_id": "` + partner + `", "share": 300}]}`:      http.StatusOK,
+		`{"service_name": "", "price": 300}`:       http.StatusBadRequest,
+		`{"service_name": "Netflix", "price": -1}`: http.StatusBadRequest,
+		`{"service_name": "Netflix", "price": 300, "split_type": "percentage", "members": [{"user_id": "` + partner + `", "share": 60}]}`: http.StatusBadRequest,
+		`{"service_name": "Netflix", "price": 300, "split_type": "fixed", "members": [{"user_id": "` + partner + `", "share": 100}]}`:     http.StatusBadRequest,
+		`{"service_name": "Netflix", "price": 300, "members": [{"user_id": "` + partner + `"}, {"user_id": "` + partner + `"}]}`:          http.StatusBadRequest,
+		`{"service_name": "Netflix", "price": 300, "members": [{"user_id": "partner"}]}`:                                                  http.StatusBadRequest,
+		`{"service_name": "Netflix", "price": 300, "household_id": "family"}`:                                                             http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/subscriptions/1", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+
+		h.HandleUpdateSubscribe(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", body, status, w.Result().StatusCode)
+		}
+	}
+}
+
+func TestHandleCreateHousehold_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+	dto := datatransfer.DTOHousehold{
+		Name:    "Family",
+		Members: []string{"a37a0327-99af-4e62-8b33-55dc3863cdc6"},
+	}
+	body, _ := json.Marshal(dto)
+
+	req := httptest.NewRequest(http.MethodPost, "/households", bytes.NewReader(body))
+	w := httptest.NewRecorder()
+
+	h.HandleCreateHousehold(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, resp.StatusCode)
+	}
+
+}
+
+func TestHandleHouseholdBalances_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/households/1/balances?from=01-2025&to=12-2025", nil)
+	w := httptest.NewRecorder()
+
+	h.HandleHouseholdBalances(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/households/1/balances", nil)
+	w = httptest.NewRecorder()
+
+	h.HandleHouseholdBalances(w, req)
+
+	resp = w.Result()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+
+}
+
+func TestHandleBudgetStatus_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	r := chi.NewRouter()
+	r.Get("/budgets/{id}/status", h.HandleBudgetStatus)
+
+	req := httptest.NewRequest(http.MethodGet, "/budgets/1/status", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/budgets/missing/status", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	resp = w.Result()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, resp.StatusCode)
+	}
+
+}
+
+func TestHandleSumByCategory_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	req := httptest.NewRequest(http.MethodGet, "/subscriptions/sum/by-category?from=01-2025&to=12-2025", nil)
+	w := httptest.NewRecorder()
+
+	h.HandleSumByCategory(w, req)
+
+	resp := w.Result()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+
+}
+
+func TestHandleAnalytics_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	for url, status := range map[string]int{
+		"/admin/analytics?from=01-2025&to=12-2025":        http.StatusOK,
+		"/admin/analytics?from=12-2025&to=01-2025":        http.StatusBadRequest,
+		"/admin/analytics?from=01-2025&to=12-2025&top=-1": http.StatusBadRequest,
+		"/admin/analytics?from=01-2025":                   http.StatusBadRequest,
+	} {
+		w := httptest.NewRecorder()
+		h.HandleAnalytics(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != status {
+			t.Errorf("%s: expected status %d, got %d", url, status, w.Code)
+		}
+	}
+
+}
+
+func TestHandleAggregate_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	for url, status := range map[string]int{
+		"/subscriptions/aggregate?from=01-2025&to=12-2025":                                        http.StatusOK,
+		"/subscriptions/aggregate?from=01-2025&to=12-2025&group_by=service_name,month&metric=avg": http.StatusOK,
+		"/subscriptions/aggregate?from=01-2025&to=12-2025&group_by=price":                         http.StatusBadRequest,
+		"/subscriptions/aggregate?from=01-2025&to=12-2025&group_by=month,month":                   http.StatusBadRequest,
+		"/subscriptions/aggregate?from=01-2025&to=12-2025&metric=max":                             http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		h.HandleAggregate(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", url, status, w.Result().StatusCode)
+		}
+	}
+}
+
+func TestHandleUploadStatement_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	for name, status := range map[string]int{
+		"statement.csv": http.StatusCreated,
+		"statement.pdf": http.StatusBadRequest,
+	} {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte("date,amount,description\n2024-01-05,-799.00,NETFLIX.COM\n"))
+		form.Close()
+
+		req := httptest.NewRequest(http.MethodPost, "/users/1/statements", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		h.HandleUploadStatement(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", name, status, w.Result().StatusCode)
+		}
+	}
+}
+
+func TestHandleBillingStatement_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	for url, status := range map[string]int{
+		"/users/1/statement.pdf?from=01-2025&to=03-2025": http.StatusOK,
+		"/users/1/statement.pdf?from=03-2025&to=01-2025": http.StatusBadRequest,
+		"/users/1/statement.pdf?from=2025-01":            http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+
+		h.HandleBillingStatement(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", url, status, w.Result().StatusCode)
+		}
+		if status == http.StatusOK && !bytes.HasPrefix(w.Body.Bytes(), []byte("%PDF-")) {
+			t.Fatalf("%s: expected a PDF document, got %q", url, w.Body.Bytes()[:min(16, w.Body.Len())])
+		}
+	}
+}
+
+func TestHandleSimulate_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	r := chi.NewRouter()
+	r.Post("/users/{id}/simulate", h.HandleSimulate)
+
+	const user = "a37a0327-99af-4e62-8b33-55dc3863cdc6"
+	for userId, status := range map[string]int{
+		"":                                     http.StatusOK,
+		user:                                   http.StatusOK,
+		"5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34": http.StatusBadRequest,
+	} {
+		dto := datatransfer.DTOSimulation{Changes: []datatransfer.DTOSimulateChange{
+			{Action: "add", Subscription: &datatransfer.DTOSubs{ServiceName: "iCloud", Price: 30, UserId: userId, StartDate: "10-2025"}},
+		}}
+		body, _ := json.Marshal(dto)
+
+		req := httptest.NewRequest(http.MethodPost, "/users/"+user+"/simulate", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("user_id %q: expected status %d, got %d", userId, status, w.Result().StatusCode)
+		}
+	}
+}
+
+func TestHandleSetJournalAccounts_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	for body, status := range map[string]int{
+		`{"funding": "Assets:Bank:Card", "categories": {"video": "Expenses:Video"}}`: http.StatusOK,
+		`{"funding": "Liabilities:CreditCard"}`:                                      http.StatusOK,
+		`{"default": "Subscriptions:Video"}`:                                         http.StatusBadRequest,
+		`{"funding": "Bank:Card"}`:                                                   http.StatusBadRequest,
+		`{"categories": {"video": "Spending:Video"}}`:                                http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPut, "/users/1/journal/accounts", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+
+		h.HandleSetJournalAccounts(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", body, status, w.Result().StatusCode)
+		}
+	}
+}
+
+func TestHandleAddPayment_Unit(t *testing.T) {
+
+	h := handlers.NewHTTPHandlers(&fakeService{})
+
+	for body, status := range map[string]int{
+		`{"amount": 799, "paid_at": "2025-01-10", "reference": "TX-1"}`:                        http.StatusCreated,
+		`{"amount": 799, "paid_at": "2025-01-10", "reference": "TX-1\n2025-01-10 * Injected"}`: http.StatusBadRequest,
+		`{"amount": 799, "paid_at": "2025-01-10", "method": "card\r"}`:                         http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions/1/payments", bytes.NewReader([]byte(body)))
+		w := httptest.NewRecorder()
+
+		h.HandleAddPayment(w, req)
+
+		if w.Result().StatusCode != status {
+			t.Fatalf("%s: expected status %d, got %d", body, status, w.Result().StatusCode)
+		}
+	}
 }
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleCreateHousehold godoc
+// @Summary      Create household
+// @Description  Создать домохозяйство для совместных подписок
+// @Tags         households
+// @Accept       json
+// @Produce      json
+// @Param        household  body      datatransfer.DTOHousehold  true  "Household"
+// @Success      201  {object}  model.Household
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /households [post]
+func (h *HTTPHandlers) HandleCreateHousehold(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var dto datatransfer.DTOHousehold
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("household bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	household, err := h.subscriptionStore.CreateHousehold(ctx, dto)
+	if err != nil {
+		log.Printf("failed to create household: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, household); err != nil {
+		return
+	}
+	log.Printf("household add successfully: id=%s", household.ID)
+}
+
+// HandleGetHousehold godoc
+// @Summary      Get household
+// @Description  Получить домохозяйство и список его участников
+// @Tags         households
+// @Produce      json
+// @Param        id   path      string  true  "Household ID"
+// @Success      200  {object}  model.Household
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /households/{id} [get]
+func (h *HTTPHandlers) HandleGetHousehold(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	household, err := h.subscriptionStore.GetHousehold(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("household not found: id=%s", id)
+			datatransfer.WriteError(w, "household not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("db error while fetching household: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, household); err != nil {
+		return
+	}
+	log.Printf("household retrieved successfully: id=%s", id)
+}
+
+// HandleHouseholdBalances godoc
+// @Summary      Household balances
+// @Description  Взаиморасчеты по совместным подпискам домохозяйства за период: кто кому должен. Доли считаются от стоимости со скидками
+// @Tags         households
+// @Produce      json
+// @Param        id    path      string  true  "Household ID"
+// @Param        from  query     string  true  "Start period (MM-YYYY)"
+// @Param        to    query     string  true  "End period (MM-YYYY)"
+// @Success      200  {object}  model.Balances
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /households/{id}/balances [get]
+func (h *HTTPHandlers) HandleHouseholdBalances(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	balances, err := h.subscriptionStore.Balances(ctx, id, from, to)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			log.Printf("household not found: id=%s", id)
+			datatransfer.WriteError(w, "household not found", http.StatusNotFound)
+			return
+		}
+		log.Printf("failed to calculate balances: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, balances); err != nil {
+		return
+	}
+	log.Printf("household balances calculated successfully: id=%s", id)
+}
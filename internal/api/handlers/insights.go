@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleInsights godoc
+// @Summary      Find wasteful subscriptions
+// @Description  Находки по действующим и будущим подпискам пользователя: один и тот же сервис (по справочнику или нормализованному названию) оплачивается несколько раз в пересекающиеся периоды или несколько сервисов относятся к одной категории. Для каждой находки и в целом оценивается ежемесячная экономия, если оставить самую дорогую подписку
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  model.Insights
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/insights [get]
+func (h *HTTPHandlers) HandleInsights(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	insights, err := h.subscriptionStore.Insights(ctx, userId)
+	if err != nil {
+		log.Printf("failed to build insights: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, insights); err != nil {
+		return
+	}
+	log.Printf("insights built successfully: user_id=%s findings=%d savings=%d", userId, len(insights.Findings), insights.EstimatedSavings)
+}
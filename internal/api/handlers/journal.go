@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"subscription/internal/report"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// journalExtensions расширения файлов журнала для каждого формата
+var journalExtensions = map[model.JournalFormat]string{
+	model.JournalLedger:    "ledger",
+	model.JournalHledger:   "journal",
+	model.JournalBeancount: "beancount",
+}
+
+// HandleJournal godoc
+// @Summary      Export charges as journal entries
+// @Description  Выгрузка списаний пользователя за период в виде проводок ledger-cli, hledger или beancount. source=expected — ожидаемые ежемесячные списания (в совместных подписках — доля пользователя), source=actual — фактические платежи по подпискам, владельцем которых является пользователь. Расходный счет выбирается по категории подписки из настроек пользователя
+// @Tags         users
+// @Produce      plain
+// @Param        id      path      string  true   "User ID"
+// @Param        from    query     string  true   "Start period (MM-YYYY)"
+// @Param        to      query     string  true   "End period (MM-YYYY)"
+// @Param        format  query     string  false  "Format: ledger, hledger or beancount (default ledger)"
+// @Param        source  query     string  false  "Source: expected or actual (default expected)"
+// @Success      200  {string}  string
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/journal [get]
+func (h *HTTPHandlers) HandleJournal(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from.After(to) {
+		datatransfer.WriteError(w, "'from' must not be after 'to'", http.StatusBadRequest)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = string(model.JournalLedger)
+	}
+	source := r.URL.Query().Get("source")
+	if source == "" {
+		source = string(model.JournalExpected)
+	}
+	if err := datatransfer.ValidateJournal(format, source); err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	journal, err := h.subscriptionStore.Journal(ctx, userId, from, to, model.JournalFormat(format), model.JournalSource(source))
+	if err != nil {
+		log.Printf("failed to build journal: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+	body, err := report.JournalText(journal)
+	if err != nil {
+		log.Printf("failed to render journal: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="subscriptions.%s"`, journalExtensions[journal.Format]))
+	w.Write([]byte(body))
+	log.Printf("journal exported successfully: user_id=%s format=%s source=%s entries=%d", userId, format, source, len(journal.Entries))
+}
+
+// HandleGetJournalAccounts godoc
+// @Summary      Get journal accounts
+// @Description  Счета пользователя для выгрузки проводок. Если они не настроены, возвращаются счета по умолчанию
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  model.JournalAccounts
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/journal/accounts [get]
+func (h *HTTPHandlers) HandleGetJournalAccounts(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	accounts, err := h.subscriptionStore.JournalAccounts(ctx, userId)
+	if err != nil {
+		log.Printf("failed to get journal accounts: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, accounts); err != nil {
+		return
+	}
+	log.Printf("journal accounts get successfully: user_id=%s", userId)
+}
+
+// HandleSetJournalAccounts godoc
+// @Summary      Set journal accounts
+// @Description  Настроить счета для выгрузки проводок: default — расходный счет для подписок без категории и основа для счетов категорий, funding — счет оплаты, categories — расходные счета отдельных категорий. Имя счета начинается с Assets, Liabilities, Equity, Income или Expenses. Незаполненные счета берутся по умолчанию
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                           true  "User ID"
+// @Param        accounts  body      datatransfer.DTOJournalAccounts  true  "Accounts"
+// @Success      200  {object}  model.JournalAccounts
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/journal/accounts [put]
+func (h *HTTPHandlers) HandleSetJournalAccounts(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOJournalAccounts
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("journal accounts bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	accounts, err := h.subscriptionStore.SetJournalAccounts(ctx, userId, dto)
+	if err != nil {
+		log.Printf("failed to save journal accounts: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, accounts); err != nil {
+		return
+	}
+	log.Printf("journal accounts saved successfully: user_id=%s", userId)
+}
@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAddPayment godoc
+// @Summary      Add subscription payment
+// @Description  Записать фактический платеж по подписке: сумма, валюта (по умолчанию RUB), дата YYYY-MM-DD, способ оплаты и идентификатор транзакции
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                   true  "Subscription ID"
+// @Param        payment  body      datatransfer.DTOPayment  true  "Payment"
+// @Success      201  {object}  model.Payment
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/payments [post]
+func (h *HTTPHandlers) HandleAddPayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOPayment
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("payment bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.subscriptionStore.AddPayment(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, payment); err != nil {
+		return
+	}
+	log.Printf("subscription payment add successfully: id=%s payment_id=%s", id, payment.ID)
+}
+
+// HandleGetPayments godoc
+// @Summary      Get subscription payments
+// @Description  Список платежей подписки по возрастанию даты
+// @Tags         payments
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {array}   model.Payment
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/payments [get]
+func (h *HTTPHandlers) HandleGetPayments(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	payments, err := h.subscriptionStore.ListPayments(ctx, id)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, payments); err != nil {
+		return
+	}
+	log.Printf("subscription payments get successfully: id=%s", id)
+}
+
+// HandleGetPayment godoc
+// @Summary      Get subscription payment
+// @Description  Получить платеж подписки
+// @Tags         payments
+// @Produce      json
+// @Param        id          path      string  true  "Subscription ID"
+// @Param        payment_id  path      string  true  "Payment ID"
+// @Success      200  {object}  model.Payment
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/payments/{payment_id} [get]
+func (h *HTTPHandlers) HandleGetPayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	paymentID := chi.URLParam(r, "payment_id")
+	ctx := r.Context()
+
+	payment, err := h.subscriptionStore.GetPayment(ctx, id, paymentID)
+	if err != nil {
+		writeStoreError(w, "payment", paymentID, err)
+		return
+	}
+
+	if err := writeJSON(w, payment); err != nil {
+		return
+	}
+	log.Printf("subscription payment get successfully: id=%s payment_id=%s", id, paymentID)
+}
+
+// HandleUpdatePayment godoc
+// @Summary      Update subscription payment
+// @Description  Изменить платеж подписки
+// @Tags         payments
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string                   true  "Subscription ID"
+// @Param        payment_id  path      string                   true  "Payment ID"
+// @Param        payment     body      datatransfer.DTOPayment  true  "Payment"
+// @Success      200  {object}  model.Payment
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/payments/{payment_id} [put]
+func (h *HTTPHandlers) HandleUpdatePayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	paymentID := chi.URLParam(r, "payment_id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOPayment
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("payment bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payment, err := h.subscriptionStore.UpdatePayment(ctx, id, paymentID, dto)
+	if err != nil {
+		writeStoreError(w, "payment", paymentID, err)
+		return
+	}
+
+	if err := writeJSON(w, payment); err != nil {
+		return
+	}
+	log.Printf("subscription payment update successfully: id=%s payment_id=%s", id, paymentID)
+}
+
+// HandleDeletePayment godoc
+// @Summary      Delete subscription payment
+// @Description  Удалить платеж подписки
+// @Tags         payments
+// @Produce      json
+// @Param        id          path      string  true  "Subscription ID"
+// @Param        payment_id  path      string  true  "Payment ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/payments/{payment_id} [delete]
+func (h *HTTPHandlers) HandleDeletePayment(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	paymentID := chi.URLParam(r, "payment_id")
+	ctx := r.Context()
+
+	if err := h.subscriptionStore.DeletePayment(ctx, id, paymentID); err != nil {
+		writeStoreError(w, "payment", paymentID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	log.Printf("subscription payment delete successfully: id=%s payment_id=%s", id, paymentID)
+}
+
+// HandleReconciliation godoc
+// @Summary      Reconcile expected charges with payments
+// @Description  Сверка ожидаемых ежемесячных списаний с фактическими платежами по месяцам. Для каждой подписки и месяца указан статус: paid, missing (платежа нет), underpaid, overpaid, unexpected (платеж без ожидаемого списания) или currency_mismatch (платеж не в RUB)
+// @Tags         payments
+// @Produce      json
+// @Param        id            query     string  false  "User ID"
+// @Param        service_name  query     string  false  "Service Name"
+// @Param        tag           query     string  false  "Tag"
+// @Param        from          query     string  true   "Start period (MM-YYYY)"
+// @Param        to            query     string  true   "End period (MM-YYYY)"
+// @Success      200  {object}  model.Reconciliation
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/reconciliation [get]
+func (h *HTTPHandlers) HandleReconciliation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter := readFilter(r, "id")
+	from, to, err := readPeriod(r)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from.After(to) {
+		datatransfer.WriteError(w, "'from' must not be after 'to'", http.StatusBadRequest)
+		return
+	}
+
+	reconciliation, err := h.subscriptionStore.Reconcile(ctx, filter, from, to)
+	if err != nil {
+		log.Printf("failed to reconcile payments: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, reconciliation); err != nil {
+		return
+	}
+	log.Printf("payments reconciled successfully: user_id=%s difference=%d", filter.UserId, reconciliation.Difference)
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleCreatePlan godoc
+// @Summary      Create plan
+// @Description  Добавить тариф сервису из справочника
+// @Tags         catalog
+// @Accept       json
+// @Produce      json
+// @Param        id    path      string                true  "Catalog entry ID"
+// @Param        plan  body      datatransfer.DTOPlan  true  "Plan"
+// @Success      201  {object}  model.Plan
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog/{id}/plans [post]
+func (h *HTTPHandlers) HandleCreatePlan(w http.ResponseWriter, r *http.Request) {
+	catalogID := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOPlan
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("plan bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	plan, err := h.subscriptionStore.CreatePlan(ctx, catalogID, dto)
+	if err != nil {
+		writeStoreError(w, "catalog entry", catalogID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, plan); err != nil {
+		return
+	}
+	log.Printf("plan add successfully: id=%s", plan.ID)
+}
+
+// HandleGetPlans godoc
+// @Summary      Get plans
+// @Description  Получить тарифы сервиса из справочника
+// @Tags         catalog
+// @Produce      json
+// @Param        id   path      string  true  "Catalog entry ID"
+// @Success      200  {array}   model.Plan
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /catalog/{id}/plans [get]
+func (h *HTTPHandlers) HandleGetPlans(w http.ResponseWriter, r *http.Request) {
+	catalogID := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	plans, err := h.subscriptionStore.ListPlans(ctx, catalogID)
+	if err != nil {
+		writeStoreError(w, "catalog entry", catalogID, err)
+		return
+	}
+
+	if err := writeJSON(w, plans); err != nil {
+		return
+	}
+	log.Printf("plans get successfully: catalog_id=%s", catalogID)
+}
+
+// HandleChangePlan godoc
+// @Summary      Change subscription plan
+// @Description  Перевести подписку на другой тариф с указанного месяца. До него расчеты используют прежнюю цену, начиная с него — новую
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                      true  "Subscription ID"
+// @Param        change  body      datatransfer.DTOChangePlan  true  "Plan change"
+// @Success      200  {object}  model.Subscription
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/change-plan [post]
+func (h *HTTPHandlers) HandleChangePlan(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOChangePlan
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("change plan bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub, err := h.subscriptionStore.ChangePlan(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription or plan", id, err)
+		return
+	}
+
+	if err := writeJSON(w, sub); err != nil {
+		return
+	}
+	log.Printf("subscription plan changed successfully: id=%s plan_id=%s", id, dto.PlanID)
+}
+
+// HandleGetPlanChanges godoc
+// @Summary      Get plan change history
+// @Description  История смены тарифов подписки: повышения и понижения
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {array}   model.PlanChange
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/plan-changes [get]
+func (h *HTTPHandlers) HandleGetPlanChanges(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	changes, err := h.subscriptionStore.ListPlanChanges(ctx, id)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, changes); err != nil {
+		return
+	}
+	log.Printf("plan changes get successfully: id=%s", id)
+}
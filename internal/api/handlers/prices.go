@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAddPrice godoc
+// @Summary      Add subscription price
+// @Description  Изменить цену подписки с указанного месяца. Прошлые списания продолжают считаться по прежней цене
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                 true  "Subscription ID"
+// @Param        price  body      datatransfer.DTOPrice  true  "Price"
+// @Success      201  {array}   model.PricePoint
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/prices [post]
+func (h *HTTPHandlers) HandleAddPrice(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOPrice
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("price bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	prices, err := h.subscriptionStore.AddPrice(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, prices); err != nil {
+		return
+	}
+	log.Printf("subscription price add successfully: id=%s price=%d from=%s", id, dto.Price, dto.EffectiveFrom)
+}
+
+// HandleGetPrices godoc
+// @Summary      Get subscription price history
+// @Description  История цен подписки с датами начала действия
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {array}   model.PricePoint
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/prices [get]
+func (h *HTTPHandlers) HandleGetPrices(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	prices, err := h.subscriptionStore.ListPrices(ctx, id)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, prices); err != nil {
+		return
+	}
+	log.Printf("subscription prices get successfully: id=%s", id)
+}
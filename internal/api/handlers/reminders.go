@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleAddReminder godoc
+// @Summary      Add subscription reminder
+// @Description  Настроить напоминания о списании и окончании подписки за lead_days дней. Каналы: email (target — адрес), webhook (target — URL), log
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id        path      string                    true  "Subscription ID"
+// @Param        reminder  body      datatransfer.DTOReminder  true  "Reminder"
+// @Success      201  {object}  model.ReminderSetting
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/reminders [post]
+func (h *HTTPHandlers) HandleAddReminder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOReminder
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("reminder bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	reminder, err := h.subscriptionStore.AddReminder(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, reminder); err != nil {
+		return
+	}
+	log.Printf("subscription reminder add successfully: id=%s reminder_id=%s channel=%s", id, reminder.ID, reminder.Channel)
+}
+
+// HandleGetReminders godoc
+// @Summary      Get subscription reminders
+// @Description  Настройки напоминаний подписки
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {array}   model.ReminderSetting
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/reminders [get]
+func (h *HTTPHandlers) HandleGetReminders(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	reminders, err := h.subscriptionStore.ListReminders(ctx, id)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, reminders); err != nil {
+		return
+	}
+	log.Printf("subscription reminders get successfully: id=%s", id)
+}
+
+// HandleDeleteReminder godoc
+// @Summary      Delete subscription reminder
+// @Description  Удалить настройку напоминаний подписки
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id           path      string  true  "Subscription ID"
+// @Param        reminder_id  path      string  true  "Reminder ID"
+// @Success      204  "No Content"
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/reminders/{reminder_id} [delete]
+func (h *HTTPHandlers) HandleDeleteReminder(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	reminderID := chi.URLParam(r, "reminder_id")
+	ctx := r.Context()
+
+	if err := h.subscriptionStore.DeleteReminder(ctx, id, reminderID); err != nil {
+		writeStoreError(w, "reminder", reminderID, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	log.Printf("subscription reminder delete successfully: id=%s reminder_id=%s", id, reminderID)
+}
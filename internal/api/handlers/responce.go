@@ -1,10 +1,17 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
 )
 
 func writeJSON(w http.ResponseWriter, data any) error {
@@ -23,3 +30,102 @@ func readJSON(r *http.Request, data any) error {
 	}
 	return nil
 }
+
+// readPeriod читает обязательные параметры периода from и to в формате MM-YYYY
+func readPeriod(r *http.Request) (time.Time, time.Time, error) {
+	fromStr := r.URL.Query().Get("from")
+	toStr := r.URL.Query().Get("to")
+	if fromStr == "" || toStr == "" {
+		return time.Time{}, time.Time{}, errors.New("missing 'from' or 'to' query parameter")
+	}
+
+	var fromDate, toDate model.CustomDate
+	if err := fromDate.UnmarshalJSON([]byte(`"` + fromStr + `"`)); err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid 'from' date format")
+	}
+	if err := toDate.UnmarshalJSON([]byte(`"` + toStr + `"`)); err != nil {
+		return time.Time{}, time.Time{}, errors.New("invalid 'to' date format")
+	}
+	return fromDate.Time, toDate.Time, nil
+}
+
+// readList читает параметр со списком значений через запятую, пустые элементы пропускаются
+func readList(r *http.Request, name string) []string {
+	var values []string
+	for _, v := range strings.Split(r.URL.Query().Get(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// readCompareWindow читает период для сравнения: явный compare_from/compare_to
+// или вычисляемый по параметру compare от периода [from, to]
+func readCompareWindow(r *http.Request, from, to time.Time) (time.Time, time.Time, error) {
+	q := r.URL.Query()
+	if q.Get("compare_from") != "" || q.Get("compare_to") != "" {
+		var fromDate, toDate model.CustomDate
+		if err := fromDate.UnmarshalJSON([]byte(`"` + q.Get("compare_from") + `"`)); err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid 'compare_from' date format")
+		}
+		if err := toDate.UnmarshalJSON([]byte(`"` + q.Get("compare_to") + `"`)); err != nil {
+			return time.Time{}, time.Time{}, errors.New("invalid 'compare_to' date format")
+		}
+		if fromDate.After(toDate.Time) {
+			return time.Time{}, time.Time{}, errors.New("'compare_from' must not be after 'compare_to'")
+		}
+		return fromDate.Time, toDate.Time, nil
+	}
+
+	mode := model.CompareMode(q.Get("compare"))
+	switch mode {
+	case "":
+		mode = model.ComparePrevious
+	case model.ComparePrevious, model.CompareYear:
+	default:
+		return time.Time{}, time.Time{}, errors.New("compare must be one of: previous, year")
+	}
+	prevFrom, prevTo := mode.Window(from, to)
+	return prevFrom, prevTo, nil
+}
+
+// readFilter читает условия отбора подписок, userParam — имя параметра с ID пользователя
+func readFilter(r *http.Request, userParam string) model.Filter {
+	q := r.URL.Query()
+	return model.Filter{
+		UserId:      q.Get(userParam),
+		ServiceName: q.Get("service_name"),
+		Category:    model.NormalizeLabel(q.Get("category")),
+		Tag:         model.NormalizeLabel(q.Get("tag")),
+	}
+}
+
+// writeStoreError отвечает клиенту на ошибку сервиса: 404 если запись не найдена,
+// 400 при нарушении бизнес-правила, иначе 500
+func writeStoreError(w http.ResponseWriter, entity, id string, err error) {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		log.Printf("%s not found: id=%s", entity, id)
+		datatransfer.WriteError(w, entity+" not found", http.StatusNotFound)
+	case model.IsValidationError(err):
+		log.Printf("%s validate error: id=%s: %v", entity, id, err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Printf("db error while processing %s: %v", entity, err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+	}
+}
+
+// readPositiveInt читает положительное целое из query-параметра, def — значение по умолчанию
+func readPositiveInt(r *http.Request, name string, def int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("'%s' must be a positive integer", name)
+	}
+	return n, nil
+}
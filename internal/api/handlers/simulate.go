@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandleSimulate godoc
+// @Summary      Simulate subscription changes
+// @Description  Прогноз расходов пользователя на months месяцев вперед (по умолчанию 12) с гипотетическими изменениями в сравнении с прогнозом без них: cancel — отменить подписку с месяца from (как и при настоящей отмене, месяц from еще оплачивается), change_price — изменить цену с месяца from, add — добавить подписку пользователя из пути (user_id можно не указывать, другой user_id — ошибка 400). Ничего не сохраняется
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id          path      string                      true  "User ID"
+// @Param        simulation  body      datatransfer.DTOSimulation  true  "Hypothetical changes"
+// @Success      200  {object}  model.Simulation
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/simulate [post]
+func (h *HTTPHandlers) HandleSimulate(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOSimulation
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("simulation bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	// Добавляемые подписки всегда принадлежат пользователю из пути: user_id можно не указывать,
+	// а указанный другой пользователь считается ошибкой
+	for _, c := range dto.Changes {
+		if c.Subscription == nil {
+			continue
+		}
+		if c.Subscription.UserId != "" && c.Subscription.UserId != userId {
+			datatransfer.WriteError(w, "subscription user_id must match the user in the path", http.StatusBadRequest)
+			return
+		}
+		c.Subscription.UserId = userId
+	}
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	simulation, err := h.subscriptionStore.Simulate(ctx, userId, dto)
+	if err != nil {
+		// Отсутствовать может только тарифный план добавляемой подписки
+		writeStoreError(w, "plan", userId, err)
+		return
+	}
+
+	if err := writeJSON(w, simulation); err != nil {
+		return
+	}
+	log.Printf("simulation built successfully: user_id=%s changes=%d delta=%d", userId, len(dto.Changes), simulation.Delta)
+}
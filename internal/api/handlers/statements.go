@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/statement"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxStatementSize ограничивает размер загружаемой выписки
+const maxStatementSize = 10 << 20
+
+// HandleUploadStatement godoc
+// @Summary      Upload bank statement
+// @Description  Загрузить выписку по счету или карте (CSV или OFX) и найти в ней регулярные списания: операции одного продавца с близкими суммами раз в месяц, квартал или год. По ним предлагаются подписки с названиями, сопоставленными со справочником и существующими сервисами. Новые предложения заменяют неподтвержденные предложения прошлой загрузки
+// @Tags         users
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        id      path      string  true   "User ID"
+// @Param        file    formData  file    true   "Statement file"
+// @Param        format  query     string  false  "Format: csv or ofx (default by file extension)"
+// @Success      201  {array}   model.Proposal
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/statements [post]
+func (h *HTTPHandlers) HandleUploadStatement(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxStatementSize)
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		log.Printf("statement bad request error: %v", err)
+		datatransfer.WriteError(w, "multipart field 'file' is required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	format, err := statement.DetectFormat(r.URL.Query().Get("format"), header.Filename)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	txns, err := statement.Parse(file, format)
+	if err != nil {
+		log.Printf("statement parse error: %v", err)
+		datatransfer.WriteError(w, "invalid statement: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	proposals, err := h.subscriptionStore.ImportStatement(ctx, userId, txns)
+	if err != nil {
+		log.Printf("failed to import statement: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, proposals); err != nil {
+		return
+	}
+	log.Printf("statement imported successfully: user_id=%s transactions=%d proposals=%d", userId, len(txns), len(proposals))
+}
+
+// HandleGetProposals godoc
+// @Summary      Get proposed subscriptions
+// @Description  Подписки, найденные в последней загруженной выписке и ожидающие подтверждения
+// @Tags         users
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {array}   model.Proposal
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/proposals [get]
+func (h *HTTPHandlers) HandleGetProposals(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	proposals, err := h.subscriptionStore.ListProposals(ctx, userId)
+	if err != nil {
+		log.Printf("failed to list proposals: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, proposals); err != nil {
+		return
+	}
+	log.Printf("proposals get successfully: user_id=%s count=%d", userId, len(proposals))
+}
+
+// HandleConfirmProposals godoc
+// @Summary      Confirm proposed subscriptions
+// @Description  Создать подписки по выбранным предложениям из выписки. Подписки создаются в одной транзакции: если хотя бы одно предложение не ожидает подтверждения или какую-то подписку не удалось сохранить, ничего не создается
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string                            true  "User ID"
+// @Param        proposals  body      datatransfer.DTOConfirmProposals  true  "Proposal IDs"
+// @Success      201  {array}   model.Subscription
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /users/{id}/proposals/confirm [post]
+func (h *HTTPHandlers) HandleConfirmProposals(w http.ResponseWriter, r *http.Request) {
+	userId := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOConfirmProposals
+	if err := readJSON(r, &dto); err != nil {
+		log.Printf("proposals bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	subs, err := h.subscriptionStore.ConfirmProposals(ctx, userId, dto)
+	if err != nil {
+		writeStoreError(w, "proposal", userId, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, subs); err != nil {
+		return
+	}
+	log.Printf("proposals confirmed successfully: user_id=%s created=%d", userId, len(subs))
+}
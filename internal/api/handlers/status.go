@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// HandlePauseSubscribe godoc
+// @Summary      Pause subscription
+// @Description  Приостановить подписку с указанного месяца, приостановленные месяцы не оплачиваются
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                        true   "Subscription ID"
+// @Param        change  body      datatransfer.DTOStatusChange  false  "Reason and effective date"
+// @Success      200  {object}  model.Subscription
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/pause [post]
+func (h *HTTPHandlers) HandlePauseSubscribe(w http.ResponseWriter, r *http.Request) {
+	h.handleStatusChange(w, r, "paused", h.subscriptionStore.Pause)
+}
+
+// HandleResumeSubscribe godoc
+// @Summary      Resume subscription
+// @Description  Возобновить приостановленную подписку с указанного месяца
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                        true   "Subscription ID"
+// @Param        change  body      datatransfer.DTOStatusChange  false  "Reason and effective date"
+// @Success      200  {object}  model.Subscription
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/resume [post]
+func (h *HTTPHandlers) HandleResumeSubscribe(w http.ResponseWriter, r *http.Request) {
+	h.handleStatusChange(w, r, "resumed", h.subscriptionStore.Resume)
+}
+
+// HandleCancelSubscribe godoc
+// @Summary      Cancel subscription
+// @Description  Отменить подписку, месяц effective_date становится последним оплачиваемым
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id      path      string                        true   "Subscription ID"
+// @Param        change  body      datatransfer.DTOStatusChange  false  "Reason and effective date"
+// @Success      200  {object}  model.Subscription
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/cancel [post]
+func (h *HTTPHandlers) HandleCancelSubscribe(w http.ResponseWriter, r *http.Request) {
+	h.handleStatusChange(w, r, "cancelled", h.subscriptionStore.Cancel)
+}
+
+// HandleGetStatusHistory godoc
+// @Summary      Get subscription status history
+// @Description  История смены статусов подписки
+// @Tags         subscriptions
+// @Produce      json
+// @Param        id   path      string  true  "Subscription ID"
+// @Success      200  {array}   model.StatusChange
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/status-history [get]
+func (h *HTTPHandlers) HandleGetStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	history, err := h.subscriptionStore.StatusHistory(ctx, id)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, history); err != nil {
+		return
+	}
+	log.Printf("subscription status history get successfully: id=%s", id)
+}
+
+type statusChangeFunc func(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error)
+
+// handleStatusChange общая обработка запросов на смену статуса, тело запроса необязательно
+func (h *HTTPHandlers) handleStatusChange(w http.ResponseWriter, r *http.Request, action string, change statusChangeFunc) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOStatusChange
+	if err := readJSON(r, &dto); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("status change bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	sub, err := change(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	if err := writeJSON(w, sub); err != nil {
+		return
+	}
+	log.Printf("subscription %s successfully: id=%s", action, id)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+)
+
+// HandleTrialsEnding godoc
+// @Summary      Trials ending soon
+// @Description  Подписки, у которых пробный период заканчивается и первое списание наступит в ближайшие N дней
+// @Tags         subscriptions
+// @Produce      json
+// @Param        days     query     int     false  "Days ahead (default 7)"
+// @Param        user_id  query     string  false  "User ID"
+// @Success      200  {array}   model.TrialEnding
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /trials/ending [get]
+func (h *HTTPHandlers) HandleTrialsEnding(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	days, err := readPositiveInt(r, "days", 7)
+	if err != nil {
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	userID := r.URL.Query().Get("user_id")
+
+	trials, err := h.subscriptionStore.TrialsEnding(ctx, userID, days)
+	if err != nil {
+		log.Printf("failed to get trials ending: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, trials); err != nil {
+		return
+	}
+	log.Printf("trials ending get successfully: days=%d count=%d", days, len(trials))
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// maxUnusedDays ограничивает срок без использования десятью годами
+const maxUnusedDays = 3650
+
+// HandleRecordUsage godoc
+// @Summary      Record subscription usage
+// @Description  Учесть использование подписки. Без тела запроса учитывается одно использование в текущий момент; used_at (RFC 3339) и count позволяют передать события задним числом или пачкой
+// @Tags         subscriptions
+// @Accept       json
+// @Produce      json
+// @Param        id     path      string                 true   "Subscription ID"
+// @Param        usage  body      datatransfer.DTOUsage  false  "Usage event"
+// @Success      201  {object}  model.Usage
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      404  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/{id}/usage [post]
+func (h *HTTPHandlers) HandleRecordUsage(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	ctx := r.Context()
+
+	var dto datatransfer.DTOUsage
+	if err := readJSON(r, &dto); err != nil && !errors.Is(err, io.EOF) {
+		log.Printf("usage bad request error: %v", err)
+		datatransfer.WriteError(w, "invalid json body", http.StatusBadRequest)
+		return
+	}
+
+	if err := dto.Validate(); err != nil {
+		log.Printf("validate error: %v", err)
+		datatransfer.WriteError(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	usage, err := h.subscriptionStore.RecordUsage(ctx, id, dto)
+	if err != nil {
+		writeStoreError(w, "subscription", id, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+
+	if err := writeJSON(w, usage); err != nil {
+		return
+	}
+	log.Printf("subscription usage recorded successfully: id=%s count=%d", id, usage.Count)
+}
+
+// HandleUnused godoc
+// @Summary      List unused subscriptions
+// @Description  Действующие подписки, которыми не пользовались последние days дней (или не пользовались вовсе), с ежемесячной стоимостью, суммой оплат с начала подписки и стоимостью одного использования. Самые дорогие идут первыми
+// @Tags         subscriptions
+// @Produce      json
+// @Param        days     query     int     false  "Days without usage (default 30, max 3650)"
+// @Param        user_id  query     string  false  "User ID"
+// @Success      200  {array}   model.UnusedSubscription
+// @Failure      400  {object}  datatransfer.ErrorResponse
+// @Failure      500  {object}  datatransfer.ErrorResponse
+// @Router       /subscriptions/unused [get]
+func (h *HTTPHandlers) HandleUnused(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	userId := r.URL.Query().Get("user_id")
+
+	days, err := readPositiveInt(r, "days", 30)
+	if err != nil || days > maxUnusedDays {
+		datatransfer.WriteError(w, "days must be a positive integer not greater than 3650", http.StatusBadRequest)
+		return
+	}
+
+	unused, err := h.subscriptionStore.Unused(ctx, userId, days)
+	if err != nil {
+		log.Printf("failed to list unused subscriptions: %v", err)
+		datatransfer.WriteError(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if err := writeJSON(w, unused); err != nil {
+		return
+	}
+	log.Printf("unused subscriptions listed successfully: user_id=%s days=%d count=%d", userId, days, len(unused))
+}
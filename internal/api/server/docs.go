@@ -0,0 +1,35 @@
+package server
+
+import (
+	"io/fs"
+	"net/http"
+	"subscription/docs"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// mountDocs раздает спецификацию OpenAPI в JSON и YAML и Swagger UI по адресу /docs
+func mountDocs(r chi.Router) error {
+	ui, err := fs.Sub(docs.Files, "ui")
+	if err != nil {
+		return err
+	}
+
+	r.Get("/docs", http.RedirectHandler("/docs/", http.StatusMovedPermanently).ServeHTTP)
+	r.Get("/docs/swagger.json", serveSpec("swagger.json", "application/json"))
+	r.Get("/docs/swagger.yaml", serveSpec("swagger.yaml", "application/yaml"))
+	r.Handle("/docs/*", http.StripPrefix("/docs/", http.FileServerFS(ui)))
+	return nil
+}
+
+func serveSpec(name, contentType string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		spec, err := docs.Files.ReadFile(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Write(spec)
+	}
+}
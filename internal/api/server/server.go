@@ -21,6 +21,83 @@ type HTTPRepository interface {
 	HandleDeleteSubscribe(w http.ResponseWriter, r *http.Request)
 	HandleUpdateSubscribe(w http.ResponseWriter, r *http.Request)
 	HandleSumInfo(w http.ResponseWriter, r *http.Request)
+	HandleSumByCategory(w http.ResponseWriter, r *http.Request)
+	HandleSumCompare(w http.ResponseWriter, r *http.Request)
+	HandleAggregate(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateHousehold(w http.ResponseWriter, r *http.Request)
+	HandleGetHousehold(w http.ResponseWriter, r *http.Request)
+	HandleHouseholdBalances(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateBudget(w http.ResponseWriter, r *http.Request)
+	HandleGetAllBudgets(w http.ResponseWriter, r *http.Request)
+	HandleGetBudget(w http.ResponseWriter, r *http.Request)
+	HandleUpdateBudget(w http.ResponseWriter, r *http.Request)
+	HandleDeleteBudget(w http.ResponseWriter, r *http.Request)
+	HandleBudgetStatus(w http.ResponseWriter, r *http.Request)
+
+	HandleCreateCatalogEntry(w http.ResponseWriter, r *http.Request)
+	HandleGetCatalog(w http.ResponseWriter, r *http.Request)
+	HandleGetCatalogEntry(w http.ResponseWriter, r *http.Request)
+	HandleUpdateCatalogEntry(w http.ResponseWriter, r *http.Request)
+	HandleDeleteCatalogEntry(w http.ResponseWriter, r *http.Request)
+	HandleResolveService(w http.ResponseWriter, r *http.Request)
+
+	HandleCreatePlan(w http.ResponseWriter, r *http.Request)
+	HandleGetPlans(w http.ResponseWriter, r *http.Request)
+	HandleChangePlan(w http.ResponseWriter, r *http.Request)
+	HandleGetPlanChanges(w http.ResponseWriter, r *http.Request)
+
+	HandleAddPrice(w http.ResponseWriter, r *http.Request)
+	HandleGetPrices(w http.ResponseWriter, r *http.Request)
+
+	HandleAddDiscount(w http.ResponseWriter, r *http.Request)
+	HandleGetDiscounts(w http.ResponseWriter, r *http.Request)
+	HandleDeleteDiscount(w http.ResponseWriter, r *http.Request)
+
+	HandleAddReminder(w http.ResponseWriter, r *http.Request)
+	HandleGetReminders(w http.ResponseWriter, r *http.Request)
+	HandleDeleteReminder(w http.ResponseWriter, r *http.Request)
+
+	HandlePauseSubscribe(w http.ResponseWriter, r *http.Request)
+	HandleResumeSubscribe(w http.ResponseWriter, r *http.Request)
+	HandleCancelSubscribe(w http.ResponseWriter, r *http.Request)
+	HandleGetStatusHistory(w http.ResponseWriter, r *http.Request)
+
+	HandleTrialsEnding(w http.ResponseWriter, r *http.Request)
+
+	HandleListJobs(w http.ResponseWriter, r *http.Request)
+	HandleAnalytics(w http.ResponseWriter, r *http.Request)
+
+	HandleForecast(w http.ResponseWriter, r *http.Request)
+
+	HandleGetDigest(w http.ResponseWriter, r *http.Request)
+	HandleAddDigestSetting(w http.ResponseWriter, r *http.Request)
+	HandleGetDigestSettings(w http.ResponseWriter, r *http.Request)
+	HandleDeleteDigestSetting(w http.ResponseWriter, r *http.Request)
+
+	HandleInsights(w http.ResponseWriter, r *http.Request)
+	HandleSimulate(w http.ResponseWriter, r *http.Request)
+
+	HandleRecordUsage(w http.ResponseWriter, r *http.Request)
+	HandleUnused(w http.ResponseWriter, r *http.Request)
+
+	HandleAddPayment(w http.ResponseWriter, r *http.Request)
+	HandleGetPayments(w http.ResponseWriter, r *http.Request)
+	HandleGetPayment(w http.ResponseWriter, r *http.Request)
+	HandleUpdatePayment(w http.ResponseWriter, r *http.Request)
+	HandleDeletePayment(w http.ResponseWriter, r *http.Request)
+	HandleReconciliation(w http.ResponseWriter, r *http.Request)
+
+	HandleUploadStatement(w http.ResponseWriter, r *http.Request)
+	HandleGetProposals(w http.ResponseWriter, r *http.Request)
+	HandleConfirmProposals(w http.ResponseWriter, r *http.Request)
+
+	HandleJournal(w http.ResponseWriter, r *http.Request)
+	HandleGetJournalAccounts(w http.ResponseWriter, r *http.Request)
+	HandleSetJournalAccounts(w http.ResponseWriter, r *http.Request)
+
+	HandleBillingStatement(w http.ResponseWriter, r *http.Request)
 }
 
 func NewHTTPServer(httpHandlers HTTPRepository) *HTTPServer {
@@ -41,8 +118,77 @@ func (s *HTTPServer) StartServer() error {
 	r.Get("/subscriptions", s.httpHandlers.HandleGetAllInfoSubscribe)
 	r.Get("/subscriptions/{id}", s.httpHandlers.HandleGetInfoSubscribe)
 	r.Get("/subscriptions/sum", s.httpHandlers.HandleSumInfo)
+	r.Get("/subscriptions/sum/by-category", s.httpHandlers.HandleSumByCategory)
+	r.Get("/subscriptions/sum/compare", s.httpHandlers.HandleSumCompare)
+	r.Get("/subscriptions/aggregate", s.httpHandlers.HandleAggregate)
+	r.Get("/subscriptions/forecast", s.httpHandlers.HandleForecast)
+	r.Get("/subscriptions/unused", s.httpHandlers.HandleUnused)
+	r.Get("/subscriptions/reconciliation", s.httpHandlers.HandleReconciliation)
 	r.Delete("/subscriptions/{id}", s.httpHandlers.HandleDeleteSubscribe)
 	r.Put("/subscriptions/{id}", s.httpHandlers.HandleUpdateSubscribe)
+
+	r.Post("/households", s.httpHandlers.HandleCreateHousehold)
+	r.Get("/households/{id}", s.httpHandlers.HandleGetHousehold)
+	r.Get("/households/{id}/balances", s.httpHandlers.HandleHouseholdBalances)
+
+	r.Post("/budgets", s.httpHandlers.HandleCreateBudget)
+	r.Get("/budgets", s.httpHandlers.HandleGetAllBudgets)
+	r.Get("/budgets/{id}", s.httpHandlers.HandleGetBudget)
+	r.Put("/budgets/{id}", s.httpHandlers.HandleUpdateBudget)
+	r.Delete("/budgets/{id}", s.httpHandlers.HandleDeleteBudget)
+	r.Get("/budgets/{id}/status", s.httpHandlers.HandleBudgetStatus)
+
+	r.Post("/catalog", s.httpHandlers.HandleCreateCatalogEntry)
+	r.Get("/catalog", s.httpHandlers.HandleGetCatalog)
+	r.Get("/catalog/resolve", s.httpHandlers.HandleResolveService)
+	r.Get("/catalog/{id}", s.httpHandlers.HandleGetCatalogEntry)
+	r.Put("/catalog/{id}", s.httpHandlers.HandleUpdateCatalogEntry)
+	r.Delete("/catalog/{id}", s.httpHandlers.HandleDeleteCatalogEntry)
+	r.Post("/catalog/{id}/plans", s.httpHandlers.HandleCreatePlan)
+	r.Get("/catalog/{id}/plans", s.httpHandlers.HandleGetPlans)
+	r.Post("/subscriptions/{id}/change-plan", s.httpHandlers.HandleChangePlan)
+	r.Get("/subscriptions/{id}/plan-changes", s.httpHandlers.HandleGetPlanChanges)
+	r.Post("/subscriptions/{id}/prices", s.httpHandlers.HandleAddPrice)
+	r.Get("/subscriptions/{id}/prices", s.httpHandlers.HandleGetPrices)
+	r.Post("/subscriptions/{id}/discounts", s.httpHandlers.HandleAddDiscount)
+	r.Get("/subscriptions/{id}/discounts", s.httpHandlers.HandleGetDiscounts)
+	r.Delete("/subscriptions/{id}/discounts/{discount_id}", s.httpHandlers.HandleDeleteDiscount)
+	r.Post("/subscriptions/{id}/reminders", s.httpHandlers.HandleAddReminder)
+	r.Get("/subscriptions/{id}/reminders", s.httpHandlers.HandleGetReminders)
+	r.Delete("/subscriptions/{id}/reminders/{reminder_id}", s.httpHandlers.HandleDeleteReminder)
+	r.Post("/subscriptions/{id}/usage", s.httpHandlers.HandleRecordUsage)
+	r.Post("/subscriptions/{id}/payments", s.httpHandlers.HandleAddPayment)
+	r.Get("/subscriptions/{id}/payments", s.httpHandlers.HandleGetPayments)
+	r.Get("/subscriptions/{id}/payments/{payment_id}", s.httpHandlers.HandleGetPayment)
+	r.Put("/subscriptions/{id}/payments/{payment_id}", s.httpHandlers.HandleUpdatePayment)
+	r.Delete("/subscriptions/{id}/payments/{payment_id}", s.httpHandlers.HandleDeletePayment)
+	r.Post("/subscriptions/{id}/pause", s.httpHandlers.HandlePauseSubscribe)
+	r.Post("/subscriptions/{id}/resume", s.httpHandlers.HandleResumeSubscribe)
+	r.Post("/subscriptions/{id}/cancel", s.httpHandlers.HandleCancelSubscribe)
+	r.Get("/subscriptions/{id}/status-history", s.httpHandlers.HandleGetStatusHistory)
+
+	r.Get("/trials/ending", s.httpHandlers.HandleTrialsEnding)
+
+	r.Get("/users/{id}/digest", s.httpHandlers.HandleGetDigest)
+	r.Post("/users/{id}/digests", s.httpHandlers.HandleAddDigestSetting)
+	r.Get("/users/{id}/digests", s.httpHandlers.HandleGetDigestSettings)
+	r.Delete("/users/{id}/digests/{digest_id}", s.httpHandlers.HandleDeleteDigestSetting)
+	r.Get("/users/{id}/insights", s.httpHandlers.HandleInsights)
+	r.Post("/users/{id}/simulate", s.httpHandlers.HandleSimulate)
+	r.Post("/users/{id}/statements", s.httpHandlers.HandleUploadStatement)
+	r.Get("/users/{id}/proposals", s.httpHandlers.HandleGetProposals)
+	r.Post("/users/{id}/proposals/confirm", s.httpHandlers.HandleConfirmProposals)
+	r.Get("/users/{id}/journal", s.httpHandlers.HandleJournal)
+	r.Get("/users/{id}/journal/accounts", s.httpHandlers.HandleGetJournalAccounts)
+	r.Put("/users/{id}/journal/accounts", s.httpHandlers.HandleSetJournalAccounts)
+	r.Get("/users/{id}/statement.pdf", s.httpHandlers.HandleBillingStatement)
+
+	r.Get("/admin/jobs", s.httpHandlers.HandleListJobs)
+	r.Get("/admin/analytics", s.httpHandlers.HandleAnalytics)
+
+	if err := mountDocs(r); err != nil {
+		return err
+	}
 	fmt.Println("Start Server")
 	fmt.Println("port", port)
 	return http.ListenAndServe(port, r)
@@ -0,0 +1,25 @@
+// Package events доставляет доменные события сервиса
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"subscription/internal/model"
+)
+
+// LogPublisher пишет события в лог приложения
+type LogPublisher struct{}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{}
+}
+
+func (p *LogPublisher) Publish(ctx context.Context, e model.Event) error {
+	payload, err := json.Marshal(e.Payload)
+	if err != nil {
+		return err
+	}
+	log.Printf("event %s: %s", e.Type, payload)
+	return nil
+}
@@ -0,0 +1,35 @@
+// aggregate.go содержит сгруппированные показатели списаний для выгрузок
+package model
+
+// Dimension измерение, по которому группируются списания
+type Dimension string
+
+const (
+	DimService  Dimension = "service_name"
+	DimUser     Dimension = "user_id"
+	DimCategory Dimension = "category"
+	DimMonth    Dimension = "month"
+)
+
+// Metric показатель, который считается для каждой группы
+type Metric string
+
+const (
+	// MetricSum сумма списаний к оплате с учетом скидок
+	MetricSum Metric = "sum"
+	// MetricCount число списаний: одно на подписку за каждый оплачиваемый месяц
+	MetricCount Metric = "count"
+	// MetricAvg средний размер списания
+	MetricAvg Metric = "avg"
+)
+
+// AggregateRow одна группа списаний. Заполнены только измерения группировки и запрошенные метрики
+type AggregateRow struct {
+	ServiceName string      `json:"service_name,omitempty"`
+	UserId      string      `json:"user_id,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	Month       *CustomDate `json:"month,omitempty"`
+	Sum         *int        `json:"sum,omitempty"`
+	Count       *int        `json:"count,omitempty"`
+	Avg         *float64    `json:"avg,omitempty"`
+}
@@ -0,0 +1,41 @@
+// analytics.go содержит показатели выручки и оттока подписок для администраторов
+package model
+
+// AnalyticsMonth показатели одного месяца.
+// MRR — сумма цен действующих подписок в пересчете на месяц, Spend — фактические списания месяца.
+// Обе суммы считаются с учетом скидок и совпадают с net_total расчета суммы.
+type AnalyticsMonth struct {
+	Month   CustomDate `json:"month"`
+	MRR     int        `json:"mrr"`
+	ARR     int        `json:"arr"`
+	Spend   int        `json:"spend"`
+	Active  int        `json:"active"`
+	New     int        `json:"new"`
+	Churned int        `json:"churned"`
+	// ChurnRate доля завершившихся в месяце подписок от действующих, в процентах
+	ChurnRate float64 `json:"churn_rate"`
+}
+
+// ServiceSpend расходы на один сервис за период
+type ServiceSpend struct {
+	ServiceName   string `json:"service_name"`
+	Total         int    `json:"total"`
+	Subscriptions int    `json:"subscriptions"`
+}
+
+// Lifetime средняя длительность подписок, завершившихся в периоде
+type Lifetime struct {
+	AverageMonths float64 `json:"average_months"`
+	Ended         int     `json:"ended"`
+}
+
+// Analytics показатели за период. MRR и ARR — по последнему месяцу периода.
+type Analytics struct {
+	From        CustomDate       `json:"from"`
+	To          CustomDate       `json:"to"`
+	MRR         int              `json:"mrr"`
+	ARR         int              `json:"arr"`
+	Months      []AnalyticsMonth `json:"months"`
+	Lifetime    Lifetime         `json:"lifetime"`
+	TopServices []ServiceSpend   `json:"top_services"`
+}
@@ -0,0 +1,37 @@
+// billing.go содержит выписку по подпискам пользователя за период для отчетов о расходах
+package model
+
+import "time"
+
+// BillingLine строка выписки: подписка, оплаченные месяцы и стоимость за период
+type BillingLine struct {
+	SubscriptionID string      `json:"subscription_id"`
+	ServiceName    string      `json:"service_name"`
+	Category       string      `json:"category,omitempty"`
+	Months         []time.Time `json:"months"`
+	Amount         int         `json:"amount"`
+}
+
+// BillingStatement выписка по подпискам пользователя: строки по подпискам и итог к оплате
+type BillingStatement struct {
+	UserId      string        `json:"user_id"`
+	From        time.Time     `json:"from"`
+	To          time.Time     `json:"to"`
+	Currency    string        `json:"currency"`
+	Lines       []BillingLine `json:"lines"`
+	Total       int           `json:"total"`
+	GeneratedAt time.Time     `json:"generated_at"`
+}
+
+// MonthRanges сворачивает подряд идущие месяцы в диапазоны: [01, 02, 03, 05] -> [[01, 03], [05, 05]]
+func (l BillingLine) MonthRanges() [][2]time.Time {
+	var ranges [][2]time.Time
+	for _, m := range l.Months {
+		if n := len(ranges); n > 0 && ranges[n-1][1].AddDate(0, 1, 0).Equal(m) {
+			ranges[n-1][1] = m
+			continue
+		}
+		ranges = append(ranges, [2]time.Time{m, m})
+	}
+	return ranges
+}
@@ -0,0 +1,67 @@
+// budget.go содержит типы для бюджетов пользователей
+package model
+
+import (
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/google/uuid"
+)
+
+// Budget месячный лимит расходов пользователя.
+// Если ServiceName и Category пустые, лимит действует на все подписки пользователя.
+type Budget struct {
+	ID           string `json:"id"`
+	UserId       string `json:"user_id"`
+	ServiceName  string `json:"service_name,omitempty"`
+	Category     string `json:"category,omitempty"`
+	MonthlyLimit int    `json:"monthly_limit"`
+}
+
+// Filter возвращает условия отбора подписок, на которые распространяется бюджет
+func (b Budget) Filter() Filter {
+	return Filter{UserId: b.UserId, ServiceName: b.ServiceName, Category: b.Category}
+}
+
+// Covers сообщает, распространяется ли бюджет на подписку
+func (b Budget) Covers(sub Subscription) bool {
+	if b.ServiceName != "" && b.ServiceName != sub.ServiceName {
+		return false
+	}
+	return b.Category == "" || b.Category == sub.Category
+}
+
+// NewBudget создает новый объект Budget с уникальным ID
+func NewBudget(dto datatransfer.DTOBudget) Budget {
+	return Budget{
+		ID:           uuid.New().String(),
+		UserId:       dto.UserId,
+		ServiceName:  dto.ServiceName,
+		Category:     NormalizeLabel(dto.Category),
+		MonthlyLimit: dto.MonthlyLimit,
+	}
+}
+
+// BudgetStatus использование бюджета за месяц
+type BudgetStatus struct {
+	Budget      Budget     `json:"budget"`
+	Month       CustomDate `json:"month"`
+	Spent       int        `json:"spent"`
+	Remaining   int        `json:"remaining"`
+	Utilisation float64    `json:"utilisation"`
+	Exceeded    bool       `json:"exceeded"`
+}
+
+// NewBudgetStatus считает использование бюджета по потраченной сумме
+func NewBudgetStatus(b Budget, month CustomDate, spent int) BudgetStatus {
+	status := BudgetStatus{
+		Budget:    b,
+		Month:     month,
+		Spent:     spent,
+		Remaining: b.MonthlyLimit - spent,
+		Exceeded:  spent > b.MonthlyLimit,
+	}
+	if b.MonthlyLimit > 0 {
+		status.Utilisation = float64(spent) * 100 / float64(b.MonthlyLimit)
+	}
+	return status
+}
@@ -0,0 +1,110 @@
+// catalog.go содержит справочник сервисов и сопоставление с ним свободного названия
+package model
+
+import (
+	"strings"
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/google/uuid"
+)
+
+// CatalogEntry запись справочника сервисов с каноническим названием и синонимами
+type CatalogEntry struct {
+	ID           string   `json:"id"`
+	Name         string   `json:"name"`
+	Aliases      []string `json:"aliases"`
+	DefaultPrice int      `json:"default_price"`
+	Category     string   `json:"category,omitempty"`
+	Website      string   `json:"website,omitempty"`
+}
+
+// NewCatalogEntry создает новый объект CatalogEntry с уникальным ID
+func NewCatalogEntry(dto datatransfer.DTOCatalogEntry) CatalogEntry {
+	return CatalogEntry{
+		ID:           uuid.New().String(),
+		Name:         strings.TrimSpace(dto.Name),
+		Aliases:      NewTags(dto.Aliases),
+		DefaultPrice: dto.DefaultPrice,
+		Category:     NormalizeLabel(dto.Category),
+		Website:      dto.Website,
+	}
+}
+
+// keys возвращает нормализованные название и синонимы записи
+func (c CatalogEntry) keys() []string {
+	keys := []string{NormalizeLabel(c.Name)}
+	for _, a := range c.Aliases {
+		keys = append(keys, NormalizeLabel(a))
+	}
+	return keys
+}
+
+// ResolveService ищет в справочнике запись по свободному названию сервиса.
+// Сначала проверяется точное совпадение с названием или синонимом,
+// затем совпадение по первым словам ("netflix premium" -> "netflix"),
+// затем нечеткое совпадение с небольшим числом опечаток.
+func ResolveService(catalog []CatalogEntry, name string) (CatalogEntry, bool) {
+	name = NormalizeLabel(name)
+	if name == "" {
+		return CatalogEntry{}, false
+	}
+
+	for _, c := range catalog {
+		for _, key := range c.keys() {
+			if key == name {
+				return c, true
+			}
+		}
+	}
+
+	best, bestLen := -1, 0
+	for i, c := range catalog {
+		for _, key := range c.keys() {
+			if strings.HasPrefix(name, key+" ") && len(key) > bestLen {
+				best, bestLen = i, len(key)
+			}
+		}
+	}
+	if best >= 0 {
+		return catalog[best], true
+	}
+
+	best, bestDist := -1, 0
+	for i, c := range catalog {
+		for _, key := range c.keys() {
+			dist := levenshtein(name, key)
+			if dist > max(1, len([]rune(key))/5) {
+				continue
+			}
+			if best < 0 || dist < bestDist {
+				best, bestDist = i, dist
+			}
+		}
+	}
+	if best >= 0 {
+		return catalog[best], true
+	}
+	return CatalogEntry{}, false
+}
+
+// levenshtein считает редакционное расстояние между строками
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
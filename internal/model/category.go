@@ -0,0 +1,46 @@
+// category.go содержит типы для группировки подписок по категориям и тегам
+package model
+
+import "strings"
+
+// NormalizeLabel приводит название категории или тега к единому виду
+func NormalizeLabel(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// NewTags нормализует теги и убирает повторы
+func NewTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = NormalizeLabel(t)
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		result = append(result, t)
+	}
+	return result
+}
+
+// Filter условия отбора подписок, пустые поля не участвуют в фильтрации
+type Filter struct {
+	UserId      string
+	ServiceName string
+	// CatalogID запись справочника, в которую разрешилось ServiceName: привязанные к ней подписки
+	// отбираются по ней, а по названию — только не привязанные
+	CatalogID string
+	Category  string
+	Tag       string
+	// IncludeExpired включает в список истекшие подписки, в расчетах за период они учитываются всегда
+	IncludeExpired bool
+}
+
+// CategoryTotal стоимость подписок одной категории за период
+type CategoryTotal struct {
+	Category string `json:"category"`
+	Total    int    `json:"total"`
+}
@@ -0,0 +1,44 @@
+// charge.go содержит типы для расчета ежемесячных списаний по подпискам
+package model
+
+import "time"
+
+// Charge одно ежемесячное списание по подписке
+type Charge struct {
+	SubscriptionID string     `json:"subscription_id"`
+	ServiceName    string     `json:"service_name"`
+	Category       string     `json:"category,omitempty"`
+	UserId         string     `json:"user_id"`
+	Month          CustomDate `json:"month"`
+	// Gross стоимость без скидок, Amount — к оплате с учетом скидок
+	Gross  int `json:"gross"`
+	Amount int `json:"amount"`
+}
+
+// MonthStart приводит дату к первому числу месяца
+func MonthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// BilledMonths возвращает месяцы периода [from, to], за которые списывается оплата.
+// Подписка оплачивается раз в период BillingCycle с месяца начала по месяц окончания включительно,
+// кроме месяцев пробного периода и месяцев, когда она была приостановлена.
+func (s Subscription) BilledMonths(from, to time.Time) []time.Time {
+	start := MonthStart(s.StartDate.Time)
+	if f := MonthStart(from); f.After(start) {
+		start = f
+	}
+	end := MonthStart(to)
+	if s.EndDate != nil && MonthStart(s.EndDate.Time).Before(end) {
+		end = MonthStart(s.EndDate.Time)
+	}
+
+	var months []time.Time
+	for m := start; !m.After(end); m = m.AddDate(0, 1, 0) {
+		if s.InTrial(m) || s.PausedIn(m) || !s.DueIn(m) {
+			continue
+		}
+		months = append(months, m)
+	}
+	return months
+}
@@ -0,0 +1,63 @@
+// comparison.go содержит сравнение расходов за два периода
+package model
+
+import (
+	"math"
+	"time"
+)
+
+// CompareMode способ выбора периода для сравнения
+type CompareMode string
+
+const (
+	// ComparePrevious предыдущий период той же длины, например прошлый квартал
+	ComparePrevious CompareMode = "previous"
+	// CompareYear тот же период годом ранее
+	CompareYear CompareMode = "year"
+)
+
+// Window возвращает период для сравнения с [from, to]
+func (m CompareMode) Window(from, to time.Time) (time.Time, time.Time) {
+	from, to = MonthStart(from), MonthStart(to)
+	if m == CompareYear {
+		return from.AddDate(-1, 0, 0), to.AddDate(-1, 0, 0)
+	}
+	months := (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	return from.AddDate(0, -months, 0), from.AddDate(0, -1, 0)
+}
+
+// PeriodTotal расходы за один из сравниваемых периодов
+type PeriodTotal struct {
+	From  CustomDate `json:"from"`
+	To    CustomDate `json:"to"`
+	Total Totals     `json:"total"`
+}
+
+// ServiceDelta изменение расходов на один сервис между периодами
+type ServiceDelta struct {
+	ServiceName string `json:"service_name"`
+	Current     int    `json:"current"`
+	Previous    int    `json:"previous"`
+	Delta       int    `json:"delta"`
+	// DeltaPercent не заполняется, если в предыдущем периоде расходов не было
+	DeltaPercent *float64 `json:"delta_percent,omitempty"`
+}
+
+// Comparison сравнение расходов за период с другим периодом.
+// Дельты считаются по суммам к оплате с учетом скидок, сервисы отсортированы по вкладу в изменение.
+type Comparison struct {
+	Current      PeriodTotal    `json:"current"`
+	Previous     PeriodTotal    `json:"previous"`
+	Delta        int            `json:"delta"`
+	DeltaPercent *float64       `json:"delta_percent,omitempty"`
+	Services     []ServiceDelta `json:"services"`
+}
+
+// PercentChange относительное изменение в процентах с точностью до сотых, nil при нулевой базе
+func PercentChange(previous, current int) *float64 {
+	if previous == 0 {
+		return nil
+	}
+	p := math.Round(float64(current-previous)*10000/float64(previous)) / 100
+	return &p
+}
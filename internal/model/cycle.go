@@ -0,0 +1,45 @@
+// cycle.go содержит периодичность оплаты подписки
+package model
+
+import "time"
+
+// BillingCycle периодичность списаний, цена подписки указывается за один период
+type BillingCycle string
+
+const (
+	CycleMonthly   BillingCycle = "monthly"
+	CycleQuarterly BillingCycle = "quarterly"
+	CycleYearly    BillingCycle = "yearly"
+)
+
+// NewBillingCycle возвращает периодичность оплаты, по умолчанию ежемесячную
+func NewBillingCycle(s string) BillingCycle {
+	if s == "" {
+		return CycleMonthly
+	}
+	return BillingCycle(s)
+}
+
+// Months длина периода оплаты в месяцах
+func (c BillingCycle) Months() int {
+	switch c {
+	case CycleQuarterly:
+		return 3
+	case CycleYearly:
+		return 12
+	default:
+		return 1
+	}
+}
+
+// DueIn сообщает, приходится ли на месяц очередное списание по периодичности оплаты.
+// Периоды отсчитываются от первого платного месяца: начала подписки или конца пробного периода.
+func (s Subscription) DueIn(month time.Time) bool {
+	anchor := MonthStart(s.StartDate.Time)
+	if conversion := s.ConversionDate(); conversion != nil {
+		anchor = conversion.Time
+	}
+	month = MonthStart(month)
+	diff := (month.Year()-anchor.Year())*12 + int(month.Month()-anchor.Month())
+	return diff >= 0 && diff%s.BillingCycle.Months() == 0
+}
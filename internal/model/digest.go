@@ -0,0 +1,75 @@
+// digest.go содержит сводку расходов пользователя за неделю или месяц
+package model
+
+import (
+	datatransfer "subscription/internal/api/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DigestPeriod период дайджеста
+type DigestPeriod string
+
+const (
+	DigestWeek  DigestPeriod = "week"
+	DigestMonth DigestPeriod = "month"
+)
+
+// PeriodBounds возвращает первый и последний день периода, в который попадает at.
+// Неделя начинается с понедельника.
+func (p DigestPeriod) PeriodBounds(at time.Time) (from, to time.Time) {
+	day := dayStart(at)
+	if p == DigestWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		from = day.AddDate(0, 0, -offset)
+		return from, from.AddDate(0, 0, 6)
+	}
+	from = MonthStart(day)
+	return from, from.AddDate(0, 1, -1)
+}
+
+// Digest сводка расходов пользователя за период в сравнении с предыдущим
+type Digest struct {
+	UserId        string         `json:"user_id"`
+	Period        DigestPeriod   `json:"period"`
+	From          time.Time      `json:"from"`
+	To            time.Time      `json:"to"`
+	Active        []Subscription `json:"active"`
+	New           []Subscription `json:"new"`
+	Ended         []Subscription `json:"ended"`
+	Total         int            `json:"total"`
+	PreviousTotal int            `json:"previous_total"`
+	Change        int            `json:"change"`
+	// Upcoming списания следующего периода
+	Upcoming []Charge `json:"upcoming"`
+}
+
+// DigestSetting настройка регулярной доставки дайджеста
+type DigestSetting struct {
+	ID      string       `json:"id"`
+	UserId  string       `json:"user_id"`
+	Period  DigestPeriod `json:"period"`
+	Channel Channel      `json:"channel"`
+	Target  string       `json:"target,omitempty"`
+}
+
+// NewDigestSetting создает новый объект DigestSetting с уникальным ID
+func NewDigestSetting(userId string, dto datatransfer.DTODigestSetting) DigestSetting {
+	return DigestSetting{
+		ID:      uuid.New().String(),
+		UserId:  userId,
+		Period:  DigestPeriod(dto.Period),
+		Channel: Channel(dto.Channel),
+		Target:  dto.Target,
+	}
+}
+
+// ActiveBetween сообщает, действовала ли подписка хотя бы один день периода [from, to]
+func (s Subscription) ActiveBetween(from, to time.Time) bool {
+	if MonthStart(s.StartDate.Time).After(to) {
+		return false
+	}
+	last, ok := s.LastDay()
+	return !ok || !last.Before(from)
+}
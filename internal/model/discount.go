@@ -0,0 +1,87 @@
+// discount.go содержит скидки и промо-периоды подписки
+package model
+
+import (
+	datatransfer "subscription/internal/api/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DiscountType способ расчета скидки
+type DiscountType string
+
+const (
+	DiscountPercentage DiscountType = "percentage"
+	DiscountFixed      DiscountType = "fixed"
+)
+
+// Discount скидка на подписку, действующая с месяца StartDate по EndDate включительно.
+// Value — процент для DiscountPercentage или сумма в рублях для DiscountFixed.
+type Discount struct {
+	ID             string       `json:"id"`
+	SubscriptionID string       `json:"subscription_id"`
+	Type           DiscountType `json:"type"`
+	Value          int          `json:"value"`
+	StartDate      CustomDate   `json:"start_date"`
+	EndDate        *CustomDate  `json:"end_date,omitempty"`
+	Code           string       `json:"code,omitempty"`
+}
+
+// NewDiscount создает новый объект Discount с уникальным ID
+func NewDiscount(subscriptionID string, dto datatransfer.DTODiscount) (Discount, error) {
+	start, err := time.Parse("01-2006", dto.StartDate)
+	if err != nil {
+		return Discount{}, err
+	}
+	var end *CustomDate
+	if dto.EndDate != "" {
+		endTime, err := time.Parse("01-2006", dto.EndDate)
+		if err != nil {
+			return Discount{}, err
+		}
+		end = &CustomDate{Time: endTime}
+	}
+	return Discount{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscriptionID,
+		Type:           DiscountType(dto.Type),
+		Value:          dto.Value,
+		StartDate:      CustomDate{Time: start},
+		EndDate:        end,
+		Code:           dto.Code,
+	}, nil
+}
+
+// ActiveIn сообщает, действует ли скидка в указанном месяце
+func (d Discount) ActiveIn(month time.Time) bool {
+	month = MonthStart(month)
+	if month.Before(MonthStart(d.StartDate.Time)) {
+		return false
+	}
+	return d.EndDate == nil || !month.After(MonthStart(d.EndDate.Time))
+}
+
+// NetPriceAt возвращает цену в месяце с учетом всех действующих скидок, но не меньше нуля
+func (s Subscription) NetPriceAt(month time.Time) int {
+	gross := s.PriceAt(month)
+	reduction := 0
+	for _, d := range s.Discounts {
+		if !d.ActiveIn(month) {
+			continue
+		}
+		switch d.Type {
+		case DiscountPercentage:
+			reduction += gross * d.Value / 100
+		case DiscountFixed:
+			reduction += d.Value
+		}
+	}
+	return max(gross-reduction, 0)
+}
+
+// Totals стоимость за период до и после применения скидок
+type Totals struct {
+	Gross int `json:"gross_total"`
+	Net   int `json:"net_total"`
+}
@@ -0,0 +1,33 @@
+// errors.go содержит ошибки бизнес-правил, которые возвращаются клиенту как 400
+package model
+
+import "errors"
+
+// ValidationError нарушение бизнес-правила, текст ошибки можно показать клиенту
+type ValidationError struct {
+	msg string
+}
+
+func (e *ValidationError) Error() string {
+	return e.msg
+}
+
+func newValidationError(msg string) error {
+	return &ValidationError{msg: msg}
+}
+
+// IsValidationError сообщает, является ли ошибка нарушением бизнес-правила
+func IsValidationError(err error) bool {
+	var ve *ValidationError
+	return errors.As(err, &ve)
+}
+
+var (
+	ErrPlanMismatch         = newValidationError("plan belongs to another service")
+	ErrEffectiveBeforeStart = newValidationError("effective date is before subscription start")
+
+	ErrEffectiveBeforeLastChange = newValidationError("effective date is before the last status change")
+
+	ErrSimulationSubscription = newValidationError("subscription is not among the user's subscriptions in the simulated period")
+	ErrProposalNotPending     = newValidationError("proposal is not pending or belongs to another user")
+)
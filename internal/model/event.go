@@ -0,0 +1,26 @@
+// event.go содержит доменные события, которые сервис публикует наружу
+package model
+
+import "time"
+
+const (
+	EventBudgetExceeded = "budget.exceeded"
+	EventStatusChanged  = "subscription.status_changed"
+	EventExpired        = "subscription.expired"
+)
+
+// Event доменное событие
+type Event struct {
+	Type       string    `json:"type"`
+	Payload    any       `json:"payload"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// NewEvent создает событие с текущим временем
+func NewEvent(eventType string, payload any) Event {
+	return Event{
+		Type:       eventType,
+		Payload:    payload,
+		OccurredAt: time.Now().UTC(),
+	}
+}
@@ -0,0 +1,21 @@
+// forecast.go содержит прогноз расходов на будущие месяцы
+package model
+
+// ForecastMonth ожидаемые списания одного месяца прогноза
+type ForecastMonth struct {
+	Month CustomDate `json:"month"`
+	Gross int        `json:"gross"`
+	// Amount к оплате с учетом скидок, Cumulative — нарастающий итог с начала прогноза
+	Amount     int      `json:"amount"`
+	Cumulative int      `json:"cumulative"`
+	Charges    []Charge `json:"charges"`
+}
+
+// Forecast прогноз расходов по месяцам
+type Forecast struct {
+	UserId string          `json:"user_id,omitempty"`
+	From   CustomDate      `json:"from"`
+	To     CustomDate      `json:"to"`
+	Months []ForecastMonth `json:"months"`
+	Total  Totals          `json:"total"`
+}
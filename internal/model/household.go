@@ -0,0 +1,163 @@
+// household.go содержит типы для совместных подписок и разделения их стоимости
+package model
+
+import (
+	"sort"
+	datatransfer "subscription/internal/api/dto"
+
+	"github.com/google/uuid"
+)
+
+// SplitType определяет способ разделения стоимости подписки между участниками
+type SplitType string
+
+const (
+	SplitEqual      SplitType = "equal"
+	SplitPercentage SplitType = "percentage"
+	SplitFixed      SplitType = "fixed"
+)
+
+// NewSplitType возвращает способ разделения, по умолчанию равными долями
+func NewSplitType(s string) SplitType {
+	if s == "" {
+		return SplitEqual
+	}
+	return SplitType(s)
+}
+
+// Member участник совместной подписки.
+// Share — процент для SplitPercentage или сумма для SplitFixed, для SplitEqual не используется.
+// Суммы SplitFixed задаются от цены на момент настройки и дальше работают как пропорция:
+// при скидке или смене цены доли участников меняются в том же отношении.
+type Member struct {
+	UserId string `json:"user_id"`
+	Share  int    `json:"share,omitempty"`
+}
+
+// NewMembers переводит участников из DTO в модель
+func NewMembers(dto []datatransfer.DTOMember) []Member {
+	if len(dto) == 0 {
+		return nil
+	}
+	members := make([]Member, 0, len(dto))
+	for _, m := range dto {
+		members = append(members, Member{UserId: m.UserId, Share: m.Share})
+	}
+	return members
+}
+
+// Household группа пользователей, которые делят между собой подписки
+type Household struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// NewHousehold создает новый объект Household с уникальным ID
+func NewHousehold(dto datatransfer.DTOHousehold) Household {
+	return Household{
+		ID:      uuid.New().String(),
+		Name:    dto.Name,
+		Members: dto.Members,
+	}
+}
+
+// Shares возвращает ежемесячную долю каждого участника в стоимости подписки.
+// Подписка без участников целиком оплачивается владельцем.
+// Остаток от целочисленного деления достается владельцу (плательщику),
+// поэтому сумма долей всегда равна цене.
+func (s Subscription) Shares() map[string]int {
+	return s.SharesOf(s.Price)
+}
+
+// SharesOf делит между участниками произвольную сумму по правилам подписки.
+// Фиксированные суммы участников задают пропорцию: сумма со скидкой делится в том же отношении.
+func (s Subscription) SharesOf(amount int) map[string]int {
+	shares := make(map[string]int, len(s.Members)+1)
+	fixed := 0
+	for _, m := range s.Members {
+		fixed += m.Share
+	}
+	if len(s.Members) == 0 || (s.SplitType == SplitFixed && fixed == 0) {
+		shares[s.UserId] = amount
+		return shares
+	}
+
+	distributed := 0
+	for _, m := range s.Members {
+		var part int
+		switch s.SplitType {
+		case SplitPercentage:
+			part = amount * m.Share / 100
+		case SplitFixed:
+			part = amount * m.Share / fixed
+		default:
+			part = amount / len(s.Members)
+		}
+		shares[m.UserId] += part
+		distributed += part
+	}
+
+	if distributed != amount {
+		shares[s.UserId] += amount - distributed
+	}
+	return shares
+}
+
+// ShareOf возвращает ежемесячную долю пользователя в стоимости подписки
+func (s Subscription) ShareOf(userId string) int {
+	return s.Shares()[userId]
+}
+
+// Debt запись о том, сколько один участник должен другому
+type Debt struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Amount int    `json:"amount"`
+}
+
+// Balances итоговые взаиморасчеты внутри домохозяйства
+type Balances struct {
+	HouseholdID string         `json:"household_id"`
+	Net         map[string]int `json:"net"`
+	Debts       []Debt         `json:"debts"`
+}
+
+// Settle сводит чистые балансы участников в минимальный список переводов.
+// Положительный баланс — участнику должны, отрицательный — должен он.
+func Settle(net map[string]int) []Debt {
+	type entry struct {
+		user   string
+		amount int
+	}
+	var creditors, debtors []entry
+	for user, amount := range net {
+		switch {
+		case amount > 0:
+			creditors = append(creditors, entry{user, amount})
+		case amount < 0:
+			debtors = append(debtors, entry{user, -amount})
+		}
+	}
+	byUser := func(e []entry) func(i, j int) bool {
+		return func(i, j int) bool { return e[i].user < e[j].user }
+	}
+	sort.Slice(creditors, byUser(creditors))
+	sort.Slice(debtors, byUser(debtors))
+
+	debts := []Debt{}
+	i, j := 0, 0
+	for i < len(debtors) && j < len(creditors) {
+		amount := min(debtors[i].amount, creditors[j].amount)
+		debts = append(debts, Debt{From: debtors[i].user, To: creditors[j].user, Amount: amount})
+		debtors[i].amount -= amount
+		creditors[j].amount -= amount
+		if debtors[i].amount == 0 {
+			i++
+		}
+		if creditors[j].amount == 0 {
+			j++
+		}
+	}
+	return debts
+}
@@ -0,0 +1,42 @@
+// insight.go содержит находки о лишних расходах пользователя
+package model
+
+import "time"
+
+// InsightKind тип находки
+type InsightKind string
+
+const (
+	// InsightDuplicate один сервис оплачивается несколько раз в пересекающиеся периоды
+	InsightDuplicate InsightKind = "duplicate"
+	// InsightSameCategory несколько разных сервисов одной категории
+	InsightSameCategory InsightKind = "same_category"
+)
+
+// Insight одна находка. Экономия оценивается так, будто оставлена самая дорогая из подписок
+type Insight struct {
+	Kind InsightKind `json:"kind"`
+	// Key нормализованное название сервиса или категория
+	Key              string   `json:"key"`
+	Message          string   `json:"message"`
+	SubscriptionIDs  []string `json:"subscription_ids"`
+	MonthlyCost      int      `json:"monthly_cost"`
+	EstimatedSavings int      `json:"estimated_monthly_savings"`
+}
+
+// Insights находки по подпискам пользователя и суммарная оценка ежемесячной экономии
+type Insights struct {
+	UserId           string    `json:"user_id"`
+	Findings         []Insight `json:"findings"`
+	EstimatedSavings int       `json:"estimated_monthly_savings"`
+}
+
+// MonthlyCost средняя стоимость подписки в месяц month для пользователя с учетом скидок и периодичности оплаты.
+// Если пользователь не указан, возвращается полная стоимость.
+func (s Subscription) MonthlyCost(userId string, month time.Time) int {
+	amount := s.NetPriceAt(month)
+	if userId != "" {
+		amount = s.SharesOf(amount)[userId]
+	}
+	return amount / s.BillingCycle.Months()
+}
@@ -0,0 +1,24 @@
+// job.go содержит состояние фоновых задач планировщика
+package model
+
+import "time"
+
+// JobStatus результат последнего запуска задачи
+type JobStatus string
+
+const (
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// JobRun сохраненное состояние фоновой задачи: расписание, последний запуск и следующий
+type JobRun struct {
+	Name           string     `json:"name"`
+	Schedule       string     `json:"schedule"`
+	LastStartedAt  *time.Time `json:"last_started_at,omitempty"`
+	LastFinishedAt *time.Time `json:"last_finished_at,omitempty"`
+	LastStatus     JobStatus  `json:"last_status,omitempty"`
+	LastResult     string     `json:"last_result,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+}
@@ -0,0 +1,127 @@
+// journal.go содержит выгрузку списаний в виде проводок для ledger, hledger и beancount
+package model
+
+import (
+	"sort"
+	"strings"
+	datatransfer "subscription/internal/api/dto"
+	"time"
+	"unicode"
+)
+
+// JournalFormat формат журнала проводок
+type JournalFormat string
+
+const (
+	JournalLedger    JournalFormat = "ledger"
+	JournalHledger   JournalFormat = "hledger"
+	JournalBeancount JournalFormat = "beancount"
+)
+
+// JournalSource откуда берутся проводки: ожидаемые списания или фактические платежи
+type JournalSource string
+
+const (
+	JournalExpected JournalSource = "expected"
+	JournalActual   JournalSource = "actual"
+)
+
+// Счета по умолчанию, если пользователь их не настроил
+const (
+	DefaultExpenseAccount = "Expenses:Subscriptions"
+	DefaultFundingAccount = "Assets:Checking"
+)
+
+// JournalAccounts счета пользователя для проводок: расходный счет для каждой категории,
+// счет по умолчанию для подписок без категории и счет, с которого идет оплата
+type JournalAccounts struct {
+	UserId     string            `json:"user_id"`
+	Default    string            `json:"default"`
+	Funding    string            `json:"funding"`
+	Categories map[string]string `json:"categories"`
+}
+
+// DefaultJournalAccounts счета для пользователя без настроек
+func DefaultJournalAccounts(userId string) JournalAccounts {
+	return JournalAccounts{
+		UserId:     userId,
+		Default:    DefaultExpenseAccount,
+		Funding:    DefaultFundingAccount,
+		Categories: map[string]string{},
+	}
+}
+
+func NewJournalAccounts(userId string, dto datatransfer.DTOJournalAccounts) JournalAccounts {
+	accounts := DefaultJournalAccounts(userId)
+	if dto.Default != "" {
+		accounts.Default = dto.Default
+	}
+	if dto.Funding != "" {
+		accounts.Funding = dto.Funding
+	}
+	for category, account := range dto.Categories {
+		accounts.Categories[NormalizeLabel(category)] = account
+	}
+	return accounts
+}
+
+// ExpenseAccount возвращает расходный счет категории. Для категории без настройки
+// счет строится из счета по умолчанию и названия категории ("video" -> "Expenses:Subscriptions:Video")
+func (a JournalAccounts) ExpenseAccount(category string) string {
+	category = NormalizeLabel(category)
+	if account, ok := a.Categories[category]; ok {
+		return account
+	}
+	if component := accountComponent(category); component != "" {
+		return a.Default + ":" + component
+	}
+	return a.Default
+}
+
+// accountComponent превращает название в часть имени счета: слова с заглавной буквы без пробелов и знаков
+func accountComponent(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		r := []rune(word)
+		b.WriteString(strings.ToUpper(string(r[0])) + string(r[1:]))
+	}
+	return b.String()
+}
+
+// JournalEntry одна проводка: расход на счет Account, оплаченный со счета Funding
+type JournalEntry struct {
+	Date           time.Time
+	Payee          string
+	SubscriptionID string
+	Reference      string
+	Account        string
+	Funding        string
+	Amount         int
+	Currency       string
+}
+
+// Journal проводки пользователя за период
+type Journal struct {
+	UserId  string
+	Format  JournalFormat
+	Source  JournalSource
+	From    time.Time
+	To      time.Time
+	Entries []JournalEntry
+}
+
+// Accounts возвращает все счета журнала в порядке сортировки
+func (j Journal) Accounts() []string {
+	seen := make(map[string]bool)
+	var accounts []string
+	for _, e := range j.Entries {
+		for _, a := range []string{e.Account, e.Funding} {
+			if !seen[a] {
+				seen[a] = true
+				accounts = append(accounts, a)
+			}
+		}
+	}
+	sort.Strings(accounts)
+	return accounts
+}
@@ -39,6 +39,27 @@ type Subscription struct {
 	UserId      string      `json:"user_id"`
 	StartDate   CustomDate  `json:"start_date"`
 	EndDate     *CustomDate `json:"end_date,omitempty"`
+	HouseholdID *string     `json:"household_id,omitempty"`
+	SplitType   SplitType   `json:"split_type,omitempty"`
+	Members     []Member    `json:"members,omitempty"`
+	Category    string      `json:"category,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+	CatalogID   *string     `json:"catalog_id,omitempty"`
+	PlanID      *string     `json:"plan_id,omitempty"`
+	Status      Status      `json:"status"`
+	TrialEnd    *CustomDate `json:"trial_end_date,omitempty"`
+	// BillingCycle периодичность оплаты, Price — стоимость одного периода
+	BillingCycle BillingCycle `json:"billing_cycle"`
+	// Prices история цен, пустая если цена не менялась
+	Prices []PricePoint `json:"-"`
+	// StatusHistory история смены статусов по возрастанию даты
+	StatusHistory []StatusChange `json:"-"`
+	// Discounts скидки и промо-периоды
+	Discounts []Discount `json:"-"`
+	// Reminders настройки напоминаний
+	Reminders []ReminderSetting `json:"-"`
+	// Usage статистика использования, nil если использований не было
+	Usage *Usage `json:"usage,omitempty"`
 }
 
 // NewSubscription создает новый объект Subscription с уникальным ID
@@ -58,6 +79,15 @@ func NewSubscription(dto datatransfer.DTOSubs) (Subscription, error) {
 		end = &CustomDate{Time: endTime}
 	}
 
+	trialEnd, err := newTrialEnd(startTime, dto.TrialMonths, dto.TrialEndDate)
+	if err != nil {
+		return Subscription{}, err
+	}
+	status := StatusActive
+	if trialEnd != nil && !trialEnd.Before(MonthStart(time.Now())) {
+		status = StatusTrial
+	}
+
 	return Subscription{
 		ID:          uuid.New().String(),
 		ServiceName: dto.ServiceName,
@@ -65,6 +95,24 @@ func NewSubscription(dto datatransfer.DTOSubs) (Subscription, error) {
 		UserId:      dto.UserId,
 		StartDate:   start,
 		EndDate:     end,
+		HouseholdID: optionalString(dto.HouseholdID),
+		SplitType:   NewSplitType(dto.SplitType),
+		Members:     NewMembers(dto.Members),
+		Category:    NormalizeLabel(dto.Category),
+		Tags:        NewTags(dto.Tags),
+		PlanID:      optionalString(dto.PlanID),
+		Status:      status,
+		TrialEnd:    trialEnd,
+
+		BillingCycle: NewBillingCycle(dto.BillingCycle),
 	}, nil
 
 }
+
+// optionalString возвращает nil для пустой строки
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,13 @@
+// notification.go содержит уведомления, которые доставляются пользователю
+package model
+
+// Notification сообщение пользователю для доставки по каналу Channel на адрес Target.
+// HTML необязателен, Data — структурированные данные для вебхуков.
+type Notification struct {
+	Channel Channel `json:"-"`
+	Target  string  `json:"-"`
+	Subject string  `json:"subject"`
+	Text    string  `json:"text"`
+	HTML    string  `json:"-"`
+	Data    any     `json:"data,omitempty"`
+}
@@ -0,0 +1,86 @@
+// payment.go содержит фактические платежи и их сверку с ожидаемыми списаниями
+package model
+
+import (
+	datatransfer "subscription/internal/api/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BaseCurrency валюта цен подписок, платежи в других валютах не сверяются по сумме
+const BaseCurrency = "RUB"
+
+// Payment фактический платеж по подписке
+type Payment struct {
+	ID             string    `json:"id"`
+	SubscriptionID string    `json:"subscription_id"`
+	Amount         int       `json:"amount"`
+	Currency       string    `json:"currency"`
+	PaidAt         time.Time `json:"paid_at"`
+	Method         string    `json:"method,omitempty"`
+	Reference      string    `json:"reference,omitempty"`
+}
+
+func NewPayment(subscriptionID string, dto datatransfer.DTOPayment) (Payment, error) {
+	paidAt, err := time.Parse(time.DateOnly, dto.PaidAt)
+	if err != nil {
+		return Payment{}, err
+	}
+	currency := dto.Currency
+	if currency == "" {
+		currency = BaseCurrency
+	}
+	return Payment{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscriptionID,
+		Amount:         dto.Amount,
+		Currency:       currency,
+		PaidAt:         paidAt,
+		Method:         dto.Method,
+		Reference:      dto.Reference,
+	}, nil
+}
+
+// ReconciliationStatus результат сверки списания с платежами
+type ReconciliationStatus string
+
+const (
+	ReconcilePaid       ReconciliationStatus = "paid"
+	ReconcileMissing    ReconciliationStatus = "missing"
+	ReconcileUnderpaid  ReconciliationStatus = "underpaid"
+	ReconcileOverpaid   ReconciliationStatus = "overpaid"
+	ReconcileUnexpected ReconciliationStatus = "unexpected"
+	// ReconcileCurrency платеж в другой валюте, сумму сверить нельзя
+	ReconcileCurrency ReconciliationStatus = "currency_mismatch"
+)
+
+// ReconciliationItem ожидаемое списание по подписке за месяц и платежи, пришедшие в этом месяце
+type ReconciliationItem struct {
+	SubscriptionID string               `json:"subscription_id"`
+	ServiceName    string               `json:"service_name"`
+	Expected       int                  `json:"expected"`
+	Paid           int                  `json:"paid"`
+	Difference     int                  `json:"difference"`
+	Status         ReconciliationStatus `json:"status"`
+	Payments       []Payment            `json:"payments"`
+}
+
+// ReconciliationMonth сверка одного месяца
+type ReconciliationMonth struct {
+	Month    CustomDate           `json:"month"`
+	Expected int                  `json:"expected"`
+	Paid     int                  `json:"paid"`
+	Items    []ReconciliationItem `json:"items"`
+}
+
+// Reconciliation сверка ожидаемых списаний (с учетом скидок) с фактическими платежами по месяцам.
+// Difference — оплачено минус ожидалось.
+type Reconciliation struct {
+	From       CustomDate            `json:"from"`
+	To         CustomDate            `json:"to"`
+	Expected   int                   `json:"expected"`
+	Paid       int                   `json:"paid"`
+	Difference int                   `json:"difference"`
+	Months     []ReconciliationMonth `json:"months"`
+}
@@ -0,0 +1,111 @@
+// plan.go содержит тарифы сервисов и историю цен подписки
+package model
+
+import (
+	"sort"
+	"strings"
+	datatransfer "subscription/internal/api/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Plan тариф сервиса из справочника, например Individual, Duo или Family
+type Plan struct {
+	ID        string `json:"id"`
+	CatalogID string `json:"catalog_id"`
+	Name      string `json:"name"`
+	Price     int    `json:"price"`
+}
+
+// NewPlan создает новый объект Plan с уникальным ID
+func NewPlan(catalogID string, dto datatransfer.DTOPlan) Plan {
+	return Plan{
+		ID:        uuid.New().String(),
+		CatalogID: catalogID,
+		Name:      strings.TrimSpace(dto.Name),
+		Price:     dto.Price,
+	}
+}
+
+// PricePoint цена подписки, действующая с указанного месяца
+type PricePoint struct {
+	EffectiveFrom CustomDate `json:"effective_from"`
+	Price         int        `json:"price"`
+}
+
+// PlanChange запись о смене тарифа подписки
+type PlanChange struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	FromPlanID     *string    `json:"from_plan_id,omitempty"`
+	ToPlanID       *string    `json:"to_plan_id,omitempty"`
+	OldPrice       int        `json:"old_price"`
+	NewPrice       int        `json:"new_price"`
+	Direction      string     `json:"direction"`
+	EffectiveDate  CustomDate `json:"effective_date"`
+	ChangedAt      time.Time  `json:"changed_at"`
+}
+
+// PlanDirection показывает, был ли переход на более дорогой или более дешевый тариф
+func PlanDirection(oldPrice, newPrice int) string {
+	switch {
+	case newPrice > oldPrice:
+		return "upgrade"
+	case newPrice < oldPrice:
+		return "downgrade"
+	default:
+		return "switch"
+	}
+}
+
+// PriceAt возвращает цену, действующую в указанном месяце.
+// Без истории цен действует текущая цена подписки.
+func (s Subscription) PriceAt(month time.Time) int {
+	month = MonthStart(month)
+	price := s.Price
+	for i, p := range s.Prices {
+		if p.EffectiveFrom.After(month) {
+			if i == 0 {
+				return p.Price
+			}
+			break
+		}
+		price = p.Price
+	}
+	return price
+}
+
+// PriceHistory возвращает историю цен подписки.
+// Если цена не менялась, история состоит из одной цены с месяца начала подписки.
+func (s Subscription) PriceHistory() []PricePoint {
+	if len(s.Prices) > 0 {
+		return s.Prices
+	}
+	return []PricePoint{{EffectiveFrom: CustomDate{Time: MonthStart(s.StartDate.Time)}, Price: s.Price}}
+}
+
+// SetPrice добавляет в историю цену, действующую с месяца effective.
+// При первой смене в историю записывается исходная цена с месяца начала подписки.
+// Текущей ценой подписки остается цена, действующая в этом месяце: будущая цена
+// учитывается в расчетах с месяца effective, но не показывается как текущая раньше срока.
+func (s *Subscription) SetPrice(effective time.Time, price int) {
+	effective = MonthStart(effective)
+	if len(s.Prices) == 0 {
+		s.Prices = []PricePoint{{EffectiveFrom: CustomDate{Time: MonthStart(s.StartDate.Time)}, Price: s.Price}}
+	}
+
+	replaced := false
+	for i := range s.Prices {
+		if s.Prices[i].EffectiveFrom.Equal(effective) {
+			s.Prices[i].Price = price
+			replaced = true
+		}
+	}
+	if !replaced {
+		s.Prices = append(s.Prices, PricePoint{EffectiveFrom: CustomDate{Time: effective}, Price: price})
+	}
+	sort.Slice(s.Prices, func(i, j int) bool { return s.Prices[i].EffectiveFrom.Before(s.Prices[j].EffectiveFrom.Time) })
+
+	s.Price = s.PriceAt(time.Now())
+}
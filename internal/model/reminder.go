@@ -0,0 +1,130 @@
+// reminder.go содержит напоминания о предстоящих списаниях и окончании подписки
+package model
+
+import (
+	"fmt"
+	datatransfer "subscription/internal/api/dto"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Channel канал доставки напоминаний
+type Channel string
+
+const (
+	ChannelEmail   Channel = "email"
+	ChannelWebhook Channel = "webhook"
+	ChannelLog     Channel = "log"
+)
+
+// ReminderKind повод для напоминания
+type ReminderKind string
+
+const (
+	ReminderRenewal ReminderKind = "renewal"
+	ReminderEnd     ReminderKind = "end"
+)
+
+// ReminderSetting настройка напоминаний подписки: куда и за сколько дней напоминать.
+// Target — адрес почты для email, URL для webhook, для log не используется.
+type ReminderSetting struct {
+	ID             string  `json:"id"`
+	SubscriptionID string  `json:"subscription_id"`
+	Channel        Channel `json:"channel"`
+	Target         string  `json:"target,omitempty"`
+	LeadDays       int     `json:"lead_days"`
+}
+
+// NewReminderSetting создает новый объект ReminderSetting с уникальным ID
+func NewReminderSetting(subscriptionID string, dto datatransfer.DTOReminder) ReminderSetting {
+	return ReminderSetting{
+		ID:             uuid.New().String(),
+		SubscriptionID: subscriptionID,
+		Channel:        Channel(dto.Channel),
+		Target:         dto.Target,
+		LeadDays:       dto.LeadDays,
+	}
+}
+
+// Reminder одно напоминание, которое нужно отправить.
+// Пара SettingID, Kind и DueDate однозначно определяет напоминание и защищает от повторной отправки.
+type Reminder struct {
+	SettingID      string       `json:"setting_id"`
+	SubscriptionID string       `json:"subscription_id"`
+	ServiceName    string       `json:"service_name"`
+	UserId         string       `json:"user_id"`
+	Channel        Channel      `json:"channel"`
+	Target         string       `json:"-"`
+	Kind           ReminderKind `json:"kind"`
+	DueDate        time.Time    `json:"due_date"`
+	Amount         int          `json:"amount,omitempty"`
+}
+
+// NextChargeDate возвращает ближайшее списание не раньше дня today и не позже дня until.
+// Списание происходит первого числа оплачиваемого месяца.
+func (s Subscription) NextChargeDate(today, until time.Time) (time.Time, bool) {
+	for _, m := range s.BilledMonths(today, until) {
+		if !m.Before(dayStart(today)) && !m.After(dayStart(until)) {
+			return m, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// LastDay последний оплаченный день подписки — конец месяца end_date
+func (s Subscription) LastDay() (time.Time, bool) {
+	if s.EndDate == nil {
+		return time.Time{}, false
+	}
+	return MonthStart(s.EndDate.Time).AddDate(0, 1, -1), true
+}
+
+// DueReminders возвращает напоминания, срок отправки которых наступил в день today
+func (s Subscription) DueReminders(today time.Time) []Reminder {
+	today = dayStart(today)
+	var due []Reminder
+	for _, setting := range s.Reminders {
+		until := today.AddDate(0, 0, setting.LeadDays)
+		reminder := Reminder{
+			SettingID:      setting.ID,
+			SubscriptionID: s.ID,
+			ServiceName:    s.ServiceName,
+			UserId:         s.UserId,
+			Channel:        setting.Channel,
+			Target:         setting.Target,
+		}
+		if charge, ok := s.NextChargeDate(today, until); ok {
+			reminder.Kind = ReminderRenewal
+			reminder.DueDate = charge
+			reminder.Amount = s.NetPriceAt(charge)
+			due = append(due, reminder)
+		}
+		if last, ok := s.LastDay(); ok && !last.Before(today) && !last.After(until) {
+			reminder.Kind = ReminderEnd
+			reminder.DueDate = last
+			reminder.Amount = 0
+			due = append(due, reminder)
+		}
+	}
+	return due
+}
+
+// Notification формирует уведомление с текстом напоминания
+func (r Reminder) Notification() Notification {
+	due := r.DueDate.Format("02.01.2006")
+	n := Notification{Channel: r.Channel, Target: r.Target, Data: r}
+	switch r.Kind {
+	case ReminderEnd:
+		n.Subject = fmt.Sprintf("%s subscription ends on %s", r.ServiceName, due)
+		n.Text = fmt.Sprintf("Your %s subscription ends on %s.\n", r.ServiceName, due)
+	default:
+		n.Subject = fmt.Sprintf("%s renews on %s", r.ServiceName, due)
+		n.Text = fmt.Sprintf("Your %s subscription renews on %s, you will be charged %d RUB.\n", r.ServiceName, due, r.Amount)
+	}
+	return n
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,32 @@
+// simulation.go содержит расчет стоимости гипотетических изменений подписок
+package model
+
+// SimulateAction тип гипотетического изменения
+type SimulateAction string
+
+const (
+	SimulateCancel      SimulateAction = "cancel"
+	SimulateChangePrice SimulateAction = "change_price"
+	SimulateAdd         SimulateAction = "add"
+)
+
+// SimulationMonth списания одного месяца без изменений и с ними
+type SimulationMonth struct {
+	Month    CustomDate `json:"month"`
+	Baseline int        `json:"baseline"`
+	Scenario int        `json:"scenario"`
+	Delta    int        `json:"delta"`
+}
+
+// Simulation сравнение прогноза расходов без изменений (baseline) и с ними (scenario).
+// Дельты считаются по суммам к оплате с учетом скидок.
+type Simulation struct {
+	UserId       string            `json:"user_id"`
+	From         CustomDate        `json:"from"`
+	To           CustomDate        `json:"to"`
+	Baseline     Totals            `json:"baseline"`
+	Scenario     Totals            `json:"scenario"`
+	Delta        int               `json:"delta"`
+	DeltaPercent *float64          `json:"delta_percent,omitempty"`
+	Months       []SimulationMonth `json:"months"`
+}
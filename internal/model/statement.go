@@ -0,0 +1,189 @@
+// statement.go содержит поиск регулярных списаний в выписках и предложения новых подписок
+package model
+
+import (
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Transaction списание из банковской выписки, Amount — сумма в рублях
+type Transaction struct {
+	Date        time.Time `json:"date"`
+	Amount      int       `json:"amount"`
+	Description string    `json:"description"`
+}
+
+// ProposalStatus статус предложенной подписки
+type ProposalStatus string
+
+const (
+	ProposalPending   ProposalStatus = "pending"
+	ProposalConfirmed ProposalStatus = "confirmed"
+)
+
+// Proposal подписка, найденная в выписке и ожидающая подтверждения пользователем
+type Proposal struct {
+	ID     string `json:"id"`
+	UserId string `json:"user_id"`
+	// Merchant описание операции в выписке, ServiceName — сопоставленное название сервиса
+	Merchant     string       `json:"merchant"`
+	ServiceName  string       `json:"service_name"`
+	Price        int          `json:"price"`
+	BillingCycle BillingCycle `json:"billing_cycle"`
+	StartDate    CustomDate   `json:"start_date"`
+	LastCharged  time.Time    `json:"last_charged"`
+	Occurrences  int          `json:"occurrences"`
+	// Matched название найдено среди существующих сервисов
+	Matched        bool           `json:"matched"`
+	Status         ProposalStatus `json:"status"`
+	SubscriptionID *string        `json:"subscription_id,omitempty"`
+}
+
+// Recurring регулярное списание одного продавца
+type Recurring struct {
+	Merchant     string
+	Key          string
+	Amount       int
+	BillingCycle BillingCycle
+	First        time.Time
+	Last         time.Time
+	Occurrences  int
+}
+
+// NewProposal предлагает подписку по регулярному списанию с названием сервиса serviceName
+func NewProposal(userId string, r Recurring, serviceName string, matched bool) Proposal {
+	return Proposal{
+		ID:           uuid.New().String(),
+		UserId:       userId,
+		Merchant:     r.Merchant,
+		ServiceName:  serviceName,
+		Price:        r.Amount,
+		BillingCycle: r.BillingCycle,
+		StartDate:    CustomDate{Time: MonthStart(r.First)},
+		LastCharged:  r.Last,
+		Occurrences:  r.Occurrences,
+		Matched:      matched,
+		Status:       ProposalPending,
+	}
+}
+
+// cycleDays допустимые интервалы между списаниями в днях и минимальное число списаний для каждой периодичности
+var cycleDays = []struct {
+	cycle    BillingCycle
+	min, max int
+	count    int
+}{
+	{CycleMonthly, 26, 35, 3},
+	{CycleQuarterly, 84, 98, 2},
+	{CycleYearly, 350, 380, 2},
+}
+
+// amountTolerance допустимое отклонение суммы между списаниями одной подписки
+const amountTolerance = 0.1
+
+// merchantNoise слова, которые банки добавляют к названию продавца
+var merchantNoise = map[string]bool{
+	"com": true, "www": true, "ru": true, "net": true, "inc": true, "llc": true, "ltd": true,
+	"ooo": true, "ооо": true, "ao": true, "ао": true, "ип": true, "pay": true, "payment": true, "оплата": true,
+}
+
+// MerchantKey нормализует описание операции: убирает номера, знаки, отдельные буквы и служебные слова,
+// оставляя первые три значимых слова ("NETFLIX.COM 866-579" -> "netflix")
+func MerchantKey(description string) string {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var key []string
+	for _, w := range words {
+		if merchantNoise[w] || len([]rune(w)) < 2 || strings.IndexFunc(w, unicode.IsDigit) >= 0 {
+			continue
+		}
+		key = append(key, w)
+		if len(key) == 3 {
+			break
+		}
+	}
+	return strings.Join(key, " ")
+}
+
+// DetectRecurring ищет регулярные списания: операции одного продавца с близкими суммами,
+// повторяющиеся раз в месяц, квартал или год
+func DetectRecurring(txns []Transaction) []Recurring {
+	byMerchant := make(map[string][]Transaction)
+	for _, t := range txns {
+		if key := MerchantKey(t.Description); key != "" {
+			byMerchant[key] = append(byMerchant[key], t)
+		}
+	}
+
+	var result []Recurring
+	for key, list := range byMerchant {
+		sort.Slice(list, func(i, j int) bool { return list[i].Date.Before(list[j].Date) })
+
+		// Разные суммы у одного продавца — разные подписки или разовые покупки
+		var groups [][]Transaction
+		for _, t := range list {
+			placed := false
+			for i, g := range groups {
+				last := g[len(g)-1].Amount
+				if abs(t.Amount-last) <= int(float64(last)*amountTolerance) {
+					groups[i] = append(g, t)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				groups = append(groups, []Transaction{t})
+			}
+		}
+
+		for _, g := range groups {
+			if cycle, ok := detectCycle(g); ok {
+				last := g[len(g)-1]
+				result = append(result, Recurring{
+					Merchant:     last.Description,
+					Key:          key,
+					Amount:       last.Amount,
+					BillingCycle: cycle,
+					First:        g[0].Date,
+					Last:         last.Date,
+					Occurrences:  len(g),
+				})
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// detectCycle определяет периодичность, если все интервалы между списаниями ей соответствуют
+func detectCycle(list []Transaction) (BillingCycle, bool) {
+	for _, c := range cycleDays {
+		if len(list) < c.count {
+			continue
+		}
+		ok := true
+		for i := 1; i < len(list); i++ {
+			days := int(list[i].Date.Sub(list[i-1].Date).Hours() / 24)
+			if days < c.min || days > c.max {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return c.cycle, true
+		}
+	}
+	return "", false
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
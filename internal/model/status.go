@@ -0,0 +1,127 @@
+// status.go содержит жизненный цикл подписки и допустимые переходы между статусами
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status статус подписки
+type Status string
+
+const (
+	StatusTrial     Status = "trial"
+	StatusActive    Status = "active"
+	StatusPaused    Status = "paused"
+	StatusCancelled Status = "cancelled"
+	StatusExpired   Status = "expired"
+)
+
+// transitions допустимые переходы между статусами, expired — конечный.
+// Отмененная подписка становится expired, когда проходит её последний оплачиваемый месяц.
+var transitions = map[Status][]Status{
+	StatusTrial:     {StatusActive, StatusCancelled, StatusExpired},
+	StatusActive:    {StatusPaused, StatusCancelled, StatusExpired},
+	StatusPaused:    {StatusActive, StatusCancelled, StatusExpired},
+	StatusCancelled: {StatusExpired},
+}
+
+// CanTransition сообщает, разрешен ли переход из статуса from в статус to
+func CanTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusChange запись о смене статуса подписки
+type StatusChange struct {
+	ID             string     `json:"id"`
+	SubscriptionID string     `json:"subscription_id"`
+	From           Status     `json:"from"`
+	To             Status     `json:"to"`
+	Reason         string     `json:"reason,omitempty"`
+	EffectiveDate  CustomDate `json:"effective_date"`
+	ChangedAt      time.Time  `json:"changed_at"`
+}
+
+// Transition переводит подписку в новый статус с указанного месяца.
+// Отмена делает месяц effective последним оплачиваемым.
+func (s *Subscription) Transition(to Status, reason string, effective time.Time) (StatusChange, error) {
+	effective = MonthStart(effective)
+	if !CanTransition(s.Status, to) {
+		return StatusChange{}, newValidationError(fmt.Sprintf("cannot change status from %s to %s", s.Status, to))
+	}
+	if effective.Before(MonthStart(s.StartDate.Time)) {
+		return StatusChange{}, ErrEffectiveBeforeStart
+	}
+	if last := s.lastStatusChange(); last != nil && effective.Before(last.EffectiveDate.Time) {
+		return StatusChange{}, ErrEffectiveBeforeLastChange
+	}
+
+	change := StatusChange{
+		ID:             uuid.New().String(),
+		SubscriptionID: s.ID,
+		From:           s.Status,
+		To:             to,
+		Reason:         reason,
+		EffectiveDate:  CustomDate{Time: effective},
+		ChangedAt:      time.Now().UTC(),
+	}
+	if to == StatusCancelled && (s.EndDate == nil || s.EndDate.After(effective)) {
+		s.EndDate = &CustomDate{Time: effective}
+	}
+	s.Status = to
+	s.StatusHistory = append(s.StatusHistory, change)
+	return change, nil
+}
+
+func (s Subscription) lastStatusChange() *StatusChange {
+	if len(s.StatusHistory) == 0 {
+		return nil
+	}
+	return &s.StatusHistory[len(s.StatusHistory)-1]
+}
+
+// Pause период приостановки подписки, To — первый месяц после возобновления
+type Pause struct {
+	From time.Time
+	To   *time.Time
+}
+
+// Pauses восстанавливает периоды приостановки по истории статусов
+func (s Subscription) Pauses() []Pause {
+	history := make([]StatusChange, len(s.StatusHistory))
+	copy(history, s.StatusHistory)
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].EffectiveDate.Before(history[j].EffectiveDate.Time)
+	})
+
+	var pauses []Pause
+	for _, c := range history {
+		switch {
+		case c.To == StatusPaused:
+			pauses = append(pauses, Pause{From: c.EffectiveDate.Time})
+		case c.From == StatusPaused && len(pauses) > 0 && pauses[len(pauses)-1].To == nil:
+			to := c.EffectiveDate.Time
+			pauses[len(pauses)-1].To = &to
+		}
+	}
+	return pauses
+}
+
+// PausedIn сообщает, приостановлена ли подписка в указанном месяце
+func (s Subscription) PausedIn(month time.Time) bool {
+	month = MonthStart(month)
+	for _, p := range s.Pauses() {
+		if !month.Before(p.From) && (p.To == nil || month.Before(*p.To)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+// trial.go содержит бесплатный пробный период подписки
+package model
+
+import (
+	"time"
+)
+
+// newTrialEnd возвращает последний месяц пробного периода по его длине или дате окончания
+func newTrialEnd(start time.Time, months int, endDate string) (*CustomDate, error) {
+	if endDate != "" {
+		end, err := time.Parse("01-2006", endDate)
+		if err != nil {
+			return nil, err
+		}
+		return &CustomDate{Time: end}, nil
+	}
+	if months > 0 {
+		return &CustomDate{Time: MonthStart(start).AddDate(0, months-1, 0)}, nil
+	}
+	return nil, nil
+}
+
+// InTrial сообщает, попадает ли месяц в бесплатный пробный период
+func (s Subscription) InTrial(month time.Time) bool {
+	return s.TrialEnd != nil && !MonthStart(month).After(MonthStart(s.TrialEnd.Time))
+}
+
+// ConversionDate первый оплачиваемый месяц после пробного периода
+func (s Subscription) ConversionDate() *CustomDate {
+	if s.TrialEnd == nil {
+		return nil
+	}
+	return &CustomDate{Time: MonthStart(s.TrialEnd.Time).AddDate(0, 1, 0)}
+}
+
+// TrialEnding подписка, пробный период которой скоро закончится
+type TrialEnding struct {
+	Subscription   Subscription `json:"subscription"`
+	TrialEnd       CustomDate   `json:"trial_end_date"`
+	ConversionDate CustomDate   `json:"conversion_date"`
+	FirstCharge    int          `json:"first_charge"`
+}
+
+// NewTrialEnding описывает окончание пробного периода и первое списание после него
+func NewTrialEnding(s Subscription) TrialEnding {
+	conversion := s.ConversionDate()
+	return TrialEnding{
+		Subscription:   s,
+		TrialEnd:       *s.TrialEnd,
+		ConversionDate: *conversion,
+		FirstCharge:    s.PriceAt(conversion.Time),
+	}
+}
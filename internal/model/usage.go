@@ -0,0 +1,52 @@
+// usage.go содержит учет использования подписок и стоимость одного использования
+package model
+
+import (
+	datatransfer "subscription/internal/api/dto"
+	"time"
+)
+
+// Usage накопленная статистика использования подписки
+type Usage struct {
+	SubscriptionID string    `json:"subscription_id"`
+	FirstUsedAt    time.Time `json:"first_used_at"`
+	LastUsedAt     time.Time `json:"last_used_at"`
+	Count          int       `json:"count"`
+}
+
+// NewUsage создает событие использования, которое добавляется к накопленной статистике
+func NewUsage(subscriptionID string, dto datatransfer.DTOUsage, now time.Time) (Usage, error) {
+	usedAt := now
+	if dto.UsedAt != "" {
+		t, err := time.Parse(time.RFC3339, dto.UsedAt)
+		if err != nil {
+			return Usage{}, err
+		}
+		usedAt = t
+	}
+	count := dto.Count
+	if count == 0 {
+		count = 1
+	}
+	return Usage{
+		SubscriptionID: subscriptionID,
+		FirstUsedAt:    usedAt.UTC(),
+		LastUsedAt:     usedAt.UTC(),
+		Count:          count,
+	}, nil
+}
+
+// UnusedSubscription подписка, которой не пользовались дольше заданного срока
+type UnusedSubscription struct {
+	SubscriptionID string     `json:"subscription_id"`
+	ServiceName    string     `json:"service_name"`
+	UserId         string     `json:"user_id"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	// DaysUnused дней с последнего использования, а если его не было — с начала подписки
+	DaysUnused  int `json:"days_unused"`
+	UseCount    int `json:"use_count"`
+	MonthlyCost int `json:"monthly_cost"`
+	// TotalCost оплачено с начала подписки по текущий месяц, CostPerUse не заполняется без использований
+	TotalCost  int      `json:"total_cost"`
+	CostPerUse *float64 `json:"cost_per_use,omitempty"`
+}
@@ -0,0 +1,20 @@
+// Package notify доставляет уведомления пользователям по разным каналам
+package notify
+
+import (
+	"context"
+	"log"
+	"subscription/internal/model"
+)
+
+// LogNotifier пишет уведомления в лог приложения
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, msg model.Notification) error {
+	log.Printf("notification: %s", msg.Subject)
+	return nil
+}
@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"subscription/internal/model"
+	"testing"
+	"time"
+)
+
+var testReminder = model.Reminder{
+	SettingID:   "1",
+	ServiceName: "Netflix",
+	UserId:      "a37a0327-99af-4e62-8b33-55dc3863cdc6",
+	Kind:        model.ReminderRenewal,
+	DueDate:     time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+	Amount:      599,
+}
+
+// smtpSink минимальный SMTP-сервер, который принимает одно письмо и отдает его в канал
+func smtpSink(t *testing.T) (string, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	messages := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		r := bufio.NewReader(conn)
+		reply := func(s string) { conn.Write([]byte(s + "\r\n")) }
+		reply("220 localhost ESMTP sink")
+
+		var data strings.Builder
+		inData := false
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					messages <- data.String()
+					reply("250 OK")
+					continue
+				}
+				data.WriteString(line)
+				continue
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				inData = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case cmd == "QUIT":
+				reply("221 Bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+	return ln.Addr().String(), messages
+}
+
+func TestSMTPNotifier_Unit(t *testing.T) {
+
+	addr, messages := smtpSink(t)
+	n := NewSMTPNotifier(addr, "noreply@example.com", "", "")
+
+	r := testReminder
+	r.Channel = model.ChannelEmail
+	r.Target = "user@example.com"
+	if err := n.Notify(context.Background(), r.Notification()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-messages:
+		if !strings.Contains(msg, "To: user@example.com") || !strings.Contains(msg, "Subject: Netflix renews on 01.03.2025") {
+			t.Fatalf("unexpected message:\n%s", msg)
+		}
+		if !strings.Contains(msg, "charged 599 RUB") {
+			t.Fatalf("expected amount in message:\n%s", msg)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered")
+	}
+}
+
+func TestSMTPNotifierHeaders_Unit(t *testing.T) {
+
+	addr, messages := smtpSink(t)
+	n := NewSMTPNotifier(addr, "noreply@example.com", "", "")
+
+	r := testReminder
+	r.ServiceName = "Яндекс Плюс\r\nBcc: victim@example.com"
+	r.Channel = model.ChannelEmail
+	r.Target = "user@example.com"
+	if err := n.Notify(context.Background(), r.Notification()); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg := <-messages:
+		headers, _, _ := strings.Cut(msg, "\r\n\r\n")
+		if strings.Contains(headers, "\r\nBcc:") {
+			t.Fatalf("service name injected a header:\n%s", headers)
+		}
+		if !strings.Contains(headers, "Subject: =?utf-8?q?") {
+			t.Fatalf("expected RFC 2047 encoded subject:\n%s", headers)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("message was not delivered")
+	}
+
+	r.Target = "user@example.com\r\nBcc: victim@example.com"
+	if err := n.Notify(context.Background(), r.Notification()); err == nil {
+		t.Fatal("expected error for target with line breaks")
+	}
+}
+
+func TestWebhookNotifier_Unit(t *testing.T) {
+
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Error(err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	r := testReminder
+	r.Channel = model.ChannelWebhook
+	r.Target = srv.URL
+	if err := NewWebhookNotifier().Notify(context.Background(), r.Notification()); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := got["data"].(map[string]any)
+	if data["kind"] != "renewal" || data["service_name"] != "Netflix" || got["subject"] != "Netflix renews on 01.03.2025" {
+		t.Fatalf("unexpected payload %v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	r.Target = failing.URL
+	if err := NewWebhookNotifier().Notify(context.Background(), r.Notification()); err == nil {
+		t.Fatal("expected error for non-2xx response")
+	}
+
+	for _, target := range []string{"file:///etc/passwd", "gopher://localhost:25", "localhost:8080/hook"} {
+		r.Target = target
+		if err := NewWebhookNotifier().Notify(context.Background(), r.Notification()); err == nil {
+			t.Fatalf("expected error for non-http target %s", target)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SMTPNotifier отправляет уведомления письмом на адрес из Target
+type SMTPNotifier struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPNotifier создает отправителя писем через сервер addr (host:port).
+// Если user пустой, письма отправляются без авторизации.
+func NewSMTPNotifier(addr, from, user, password string) *SMTPNotifier {
+	var auth smtp.Auth
+	if user != "" {
+		host := addr
+		if i := strings.LastIndex(addr, ":"); i >= 0 {
+			host = addr[:i]
+		}
+		auth = smtp.PlainAuth("", user, password, host)
+	}
+	return &SMTPNotifier{addr: addr, from: from, auth: auth}
+}
+
+// Notify отправляет письмо; если у уведомления есть HTML, письмо содержит обе версии
+func (n *SMTPNotifier) Notify(ctx context.Context, msg model.Notification) error {
+	if strings.ContainsAny(msg.Target, "\r\n") {
+		return errors.New("email target must not contain line breaks")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.Target)
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+
+	if msg.HTML == "" {
+		writePart(&b, "text/plain", msg.Text)
+	} else {
+		boundary := uuid.New().String()
+		fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", boundary)
+		fmt.Fprintf(&b, "--%s\r\n", boundary)
+		writePart(&b, "text/plain", msg.Text)
+		fmt.Fprintf(&b, "\r\n--%s\r\n", boundary)
+		writePart(&b, "text/html", msg.HTML)
+		fmt.Fprintf(&b, "\r\n--%s--\r\n", boundary)
+	}
+
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{msg.Target}, []byte(b.String()))
+}
+
+// headerValue заменяет переводы строк пробелами, чтобы значение не могло добавить заголовки,
+// и кодирует не-ASCII символы (например, кириллические названия сервисов) по RFC 2047
+func headerValue(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' {
+			return ' '
+		}
+		return r
+	}, s)
+	return mime.QEncoding.Encode("utf-8", s)
+}
+
+func writePart(b *strings.Builder, contentType, body string) {
+	fmt.Fprintf(b, "Content-Type: %s; charset=UTF-8\r\n\r\n", contentType)
+	body = strings.ReplaceAll(body, "\r\n", "\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+}
@@ -0,0 +1,150 @@
+package report
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"subscription/internal/model"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Разметка выписки на странице A4
+const (
+	marginLeft   = 50.0
+	marginRight  = pageWidth - 50
+	marginTop    = 60.0
+	marginBottom = pageHeight - 60
+
+	fontSize   = 10.0
+	lineHeight = 14.0
+
+	colService  = marginLeft
+	colCategory = 200.0
+	colMonths   = 290.0
+	colCount    = 480.0
+	colAmount   = marginRight
+)
+
+// BillingPDF отрисовывает выписку по подпискам в PDF: по строке на подписку с оплаченными месяцами,
+// их количеством и стоимостью, итог к оплате и номера страниц
+func BillingPDF(s model.BillingStatement) ([]byte, error) {
+	regular, err := newPDFFont("F1", goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+	bold, err := newPDFFont("F2", gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+	doc := &pdfDocument{fonts: []*pdfFont{regular, bold}}
+
+	page := doc.addPage()
+	y := marginTop
+	page.text(bold, 18, marginLeft, y, "Subscription statement")
+	y += 26
+	for _, line := range []string{
+		"User: " + s.UserId,
+		fmt.Sprintf("Period: %s - %s", s.From.Format("01.2006"), s.To.Format("01.2006")),
+		"Generated: " + s.GeneratedAt.Format("02.01.2006 15:04 UTC"),
+	} {
+		page.text(regular, fontSize, marginLeft, y, line)
+		y += lineHeight
+	}
+	y += lineHeight
+	y = tableHeader(page, bold, y)
+	top := y
+
+	for _, l := range s.Lines {
+		service := regular.wrap(l.ServiceName, fontSize, colCategory-colService-10)
+		category := regular.wrap(l.Category, fontSize, colMonths-colCategory-10)
+		months := regular.wrap(monthRanges(l), fontSize, colCount-colMonths-40)
+		rows := max(len(service), len(category), len(months))
+
+		// Строка, которая не помещается в остаток страницы, начинается с новой,
+		// а строка выше целой страницы продолжается на следующих
+		if y+float64(rows)*lineHeight > marginBottom && y > top {
+			page = doc.addPage()
+			y = tableHeader(page, bold, marginTop)
+			top = y
+		}
+		for i := range rows {
+			if y+lineHeight > marginBottom && y > top {
+				page = doc.addPage()
+				y = tableHeader(page, bold, marginTop)
+				top = y
+			}
+			if i < len(service) {
+				page.text(regular, fontSize, colService, y, service[i])
+			}
+			if i < len(category) {
+				page.text(regular, fontSize, colCategory, y, category[i])
+			}
+			if i < len(months) {
+				page.text(regular, fontSize, colMonths, y, months[i])
+			}
+			if i == 0 {
+				page.textRight(regular, fontSize, colCount, y, strconv.Itoa(len(l.Months)))
+				page.textRight(regular, fontSize, colAmount, y, money(l.Amount, s.Currency))
+			}
+			y += lineHeight
+		}
+		y += 4
+	}
+
+	if y+2*lineHeight > marginBottom {
+		page = doc.addPage()
+		y = marginTop
+	}
+	page.line(marginLeft, y-lineHeight+2, marginRight, y-lineHeight+2, 0.8)
+	y += 2
+	page.text(bold, fontSize, colService, y, "Total")
+	page.textRight(bold, fontSize, colAmount, y, money(s.Total, s.Currency))
+
+	for i, p := range doc.pages {
+		p.textRight(regular, 8, marginRight, pageHeight-30, fmt.Sprintf("Page %d of %d", i+1, len(doc.pages)))
+	}
+	return doc.Bytes()
+}
+
+// tableHeader выводит заголовок таблицы и возвращает позицию первой строки
+func tableHeader(page *pdfPage, bold *pdfFont, y float64) float64 {
+	page.text(bold, fontSize, colService, y, "Service")
+	page.text(bold, fontSize, colCategory, y, "Category")
+	page.text(bold, fontSize, colMonths, y, "Months billed")
+	page.textRight(bold, fontSize, colCount, y, "Months")
+	page.textRight(bold, fontSize, colAmount, y, "Cost")
+	page.line(marginLeft, y+5, marginRight, y+5, 0.5)
+	return y + lineHeight + 6
+}
+
+// monthRanges перечисляет оплаченные месяцы строки диапазонами: "01.2024-03.2024, 05.2024"
+func monthRanges(l model.BillingLine) string {
+	var parts []string
+	for _, r := range l.MonthRanges() {
+		if r[0].Equal(r[1]) {
+			parts = append(parts, r[0].Format("01.2006"))
+			continue
+		}
+		parts = append(parts, r[0].Format("01.2006")+"-"+r[1].Format("01.2006"))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// money форматирует сумму с разделением разрядов: 12345 -> "12 345 RUB"
+func money(amount int, currency string) string {
+	digits := strconv.Itoa(amount)
+	sign := ""
+	if amount < 0 {
+		sign, digits = "-", digits[1:]
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(d)
+	}
+	return sign + b.String() + " " + currency
+}
@@ -0,0 +1,92 @@
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"subscription/internal/model"
+	"testing"
+	"time"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+func TestWrapLongWord_Unit(t *testing.T) {
+	f, err := newPDFFont("F1", goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	word := strings.Repeat("Подпискабезпробелов", 5)
+	lines := f.wrap("Сервис "+word, fontSize, 100)
+	if len(lines) < 3 || lines[0] != "Сервис" || strings.Join(lines[1:], "") != word {
+		t.Fatalf("unexpected lines: %q", lines)
+	}
+	for _, line := range lines {
+		if f.width(line, fontSize) > 100 {
+			t.Fatalf("line %q is wider than the column", line)
+		}
+	}
+}
+
+func TestBillingPDFTallRow_Unit(t *testing.T) {
+	// Месяцы через один не складываются в диапазоны, и строка выходит выше страницы
+	from := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	line := model.BillingLine{ServiceName: "Netflix", Amount: 100}
+	for i := 0; i < 600; i += 2 {
+		line.Months = append(line.Months, from.AddDate(0, i, 0))
+	}
+	data, err := BillingPDF(model.BillingStatement{
+		UserId:   "a37a0327-99af-4e62-8b33-55dc3863cdc6",
+		From:     from,
+		To:       from.AddDate(0, 599, 0),
+		Currency: model.BaseCurrency,
+		Lines:    []model.BillingLine{line},
+		Total:    100,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := pageContents(t, data)
+	if len(contents) < 2 {
+		t.Fatalf("expected the row to continue on another page, got %d pages", len(contents))
+	}
+
+	// Текст таблицы не заходит в нижнее поле, ниже него только номера страниц
+	position := regexp.MustCompile(`/F\d (\d+\.\d) Tf [\d.]+ (-?[\d.]+) Td`)
+	for _, content := range contents {
+		for _, m := range position.FindAllStringSubmatch(content, -1) {
+			y, _ := strconv.ParseFloat(m[2], 64)
+			if m[1] != "8.0" && pageHeight-y > marginBottom {
+				t.Fatalf("text drawn below the bottom margin at y=%.2f", pageHeight-y)
+			}
+		}
+	}
+}
+
+// pageContents распаковывает потоки страниц документа
+func pageContents(t *testing.T, data []byte) []string {
+	t.Helper()
+	var contents []string
+	for _, part := range bytes.Split(data, []byte(">>\nstream\n"))[1:] {
+		end := bytes.Index(part, []byte("\nendstream\n"))
+		if end < 0 {
+			continue
+		}
+		r, err := zlib.NewReader(bytes.NewReader(part[:end]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Contains(content, []byte(" Td ")) {
+			contents = append(contents, string(content))
+		}
+	}
+	return contents
+}
@@ -0,0 +1,68 @@
+// Package report формирует отчеты для пользователей из шаблонов
+package report
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	"subscription/internal/model"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates
+var templates embed.FS
+
+var funcs = map[string]any{
+	"date": func(t time.Time) string { return t.Format("02.01.2006") },
+	"signed": func(n int) string {
+		if n > 0 {
+			return fmt.Sprintf("+%d RUB", n)
+		}
+		return fmt.Sprintf("%d RUB", n)
+	},
+}
+
+var (
+	digestText = texttemplate.Must(texttemplate.New("digest.txt").Funcs(funcs).ParseFS(templates, "templates/digest.txt"))
+	digestHTML = htmltemplate.Must(htmltemplate.New("digest.html").Funcs(funcs).ParseFS(templates, "templates/digest.html"))
+)
+
+// DigestText отрисовывает дайджест простым текстом
+func DigestText(d model.Digest) (string, error) {
+	var b strings.Builder
+	if err := digestText.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DigestHTML отрисовывает дайджест в HTML
+func DigestHTML(d model.Digest) (string, error) {
+	var b strings.Builder
+	if err := digestHTML.Execute(&b, d); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// DigestNotification готовит дайджест к доставке по каналу настройки
+func DigestNotification(d model.Digest, setting model.DigestSetting) (model.Notification, error) {
+	text, err := DigestText(d)
+	if err != nil {
+		return model.Notification{}, err
+	}
+	html, err := DigestHTML(d)
+	if err != nil {
+		return model.Notification{}, err
+	}
+	return model.Notification{
+		Channel: setting.Channel,
+		Target:  setting.Target,
+		Subject: fmt.Sprintf("Subscription digest %s - %s", d.From.Format("02.01.2006"), d.To.Format("02.01.2006")),
+		Text:    text,
+		HTML:    html,
+		Data:    d,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package report
+
+import (
+	"strconv"
+	"strings"
+	"subscription/internal/model"
+	texttemplate "text/template"
+	"time"
+	"unicode"
+)
+
+var journalFuncs = map[string]any{
+	"iso":   func(t time.Time) string { return t.Format(time.DateOnly) },
+	"quote": strconv.Quote,
+	"line":  singleLine,
+}
+
+var (
+	journalLedger    = texttemplate.Must(texttemplate.New("journal.ledger").Funcs(journalFuncs).ParseFS(templates, "templates/journal.ledger"))
+	journalBeancount = texttemplate.Must(texttemplate.New("journal.beancount").Funcs(journalFuncs).ParseFS(templates, "templates/journal.beancount"))
+)
+
+// JournalText отрисовывает проводки в формате журнала. Для ledger и hledger используется общий синтаксис,
+// для beancount в начало добавляются директивы open для всех счетов.
+func JournalText(j model.Journal) (string, error) {
+	tmpl := journalLedger
+	if j.Format == model.JournalBeancount {
+		tmpl = journalBeancount
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, j); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// singleLine заменяет управляющие символы пробелами, чтобы значение не разорвало строку журнала
+// и не добавило в него лишних проводок
+func singleLine(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, s)
+}
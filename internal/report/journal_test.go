@@ -0,0 +1,35 @@
+package report
+
+import (
+	"strings"
+	"subscription/internal/model"
+	"testing"
+	"time"
+)
+
+func TestJournalTextSingleLine_Unit(t *testing.T) {
+	date := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	text, err := JournalText(model.Journal{
+		Format: model.JournalLedger,
+		Source: model.JournalActual,
+		From:   date,
+		To:     date,
+		Entries: []model.JournalEntry{{
+			Date:           date,
+			Payee:          "Netflix\n2025-01-10 * Injected",
+			SubscriptionID: "a37a0327-99af-4e62-8b33-55dc3863cdc6",
+			Reference:      "TX-1\n    Assets:Other  1000 RUB",
+			Account:        "Expenses:Subscriptions",
+			Funding:        "Assets:Checking",
+			Amount:         799,
+			Currency:       model.BaseCurrency,
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Управляющие символы не добавляют в журнал ни новых операций, ни проводок
+	if strings.Count(text, "\n2025-01-10 ") != 1 || strings.Contains(text, "\n    Assets:Other") {
+		t.Fatalf("unexpected journal:\n%s", text)
+	}
+}
@@ -0,0 +1,329 @@
+// pdf.go содержит минимальный генератор PDF: страницы A4 с текстом и линиями и встроенные шрифты TrueType.
+// Текст кодируется номерами глифов (Identity-H), поэтому кириллица и другие алфавиты шрифта выводятся без замен,
+// а таблица ToUnicode позволяет искать и копировать текст в просмотрщике.
+package report
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// Размер страницы A4 в пунктах
+const (
+	pageWidth  = 595.28
+	pageHeight = 841.89
+)
+
+// pdfFont шрифт TrueType, встраиваемый в документ целиком
+type pdfFont struct {
+	resource string
+	name     string
+	data     []byte
+	font     *sfnt.Font
+	buf      sfnt.Buffer
+	// widths ширины использованных глифов в тысячных долях кегля, runes — символы для ToUnicode
+	widths map[sfnt.GlyphIndex]int
+	runes  map[sfnt.GlyphIndex]rune
+}
+
+func newPDFFont(resource string, data []byte) (*pdfFont, error) {
+	f, err := sfnt.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	pf := &pdfFont{
+		resource: resource,
+		data:     data,
+		font:     f,
+		widths:   make(map[sfnt.GlyphIndex]int),
+		runes:    make(map[sfnt.GlyphIndex]rune),
+	}
+	name, err := f.Name(&pf.buf, sfnt.NameIDPostScript)
+	if err != nil || name == "" {
+		name = resource
+	}
+	pf.name = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, name)
+	return pf, nil
+}
+
+// glyph возвращает глиф символа и его ширину, запоминая их для описания шрифта
+func (f *pdfFont) glyph(r rune) (sfnt.GlyphIndex, int) {
+	g, err := f.font.GlyphIndex(&f.buf, r)
+	if err != nil {
+		g = 0
+	}
+	if w, ok := f.widths[g]; ok {
+		return g, w
+	}
+	advance, err := f.font.GlyphAdvance(&f.buf, g, fixed.I(1000), font.HintingNone)
+	w := 0
+	if err == nil {
+		w = advance.Round()
+	}
+	f.widths[g] = w
+	if g != 0 {
+		f.runes[g] = r
+	}
+	return g, w
+}
+
+// width ширина строки в пунктах при кегле size
+func (f *pdfFont) width(s string, size float64) float64 {
+	total := 0
+	for _, r := range s {
+		_, w := f.glyph(r)
+		total += w
+	}
+	return float64(total) * size / 1000
+}
+
+// encode кодирует строку номерами глифов для оператора Tj
+func (f *pdfFont) encode(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range s {
+		g, _ := f.glyph(r)
+		fmt.Fprintf(&b, "%04X", uint16(g))
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// wrap разбивает строку по пробелам на строки не шире width. Слово шире width
+// переносится по символам
+func (f *pdfFont) wrap(s string, size, width float64) []string {
+	var lines []string
+	var current string
+	for _, word := range strings.Fields(s) {
+		candidate := word
+		if current != "" {
+			candidate = current + " " + word
+		}
+		if f.width(candidate, size) <= width {
+			current = candidate
+			continue
+		}
+		if current != "" {
+			lines = append(lines, current)
+		}
+		for f.width(word, size) > width {
+			n := f.fit(word, size, width)
+			lines = append(lines, word[:n])
+			word = word[n:]
+		}
+		current = word
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// fit возвращает длину в байтах самого длинного начала строки, которое помещается в width,
+// но не меньше одного символа
+func (f *pdfFont) fit(s string, size, width float64) int {
+	total := 0
+	for i, r := range s {
+		_, w := f.glyph(r)
+		total += w
+		if float64(total)*size/1000 > width {
+			if i == 0 {
+				_, n := utf8.DecodeRuneInString(s)
+				return n
+			}
+			return i
+		}
+	}
+	return len(s)
+}
+
+// pdfPage поток команд одной страницы. Координаты отсчитываются от верхнего левого угла
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) text(f *pdfFont, size, x, y float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %.1f Tf %.2f %.2f Td %s Tj ET\n", f.resource, size, x, pageHeight-y, f.encode(s))
+}
+
+// textRight выводит строку, выровненную по правому краю x
+func (p *pdfPage) textRight(f *pdfFont, size, x, y float64, s string) {
+	p.text(f, size, x-f.width(s, size), y, s)
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2, width float64) {
+	fmt.Fprintf(&p.content, "%.2f w %.2f %.2f m %.2f %.2f l S\n", width, x1, pageHeight-y1, x2, pageHeight-y2)
+}
+
+// pdfDocument собирает страницы и шрифты в файл PDF
+type pdfDocument struct {
+	fonts []*pdfFont
+	pages []*pdfPage
+}
+
+func (d *pdfDocument) addPage() *pdfPage {
+	p := &pdfPage{}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// pdfWriter нумерует объекты и записывает таблицу ссылок
+type pdfWriter struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+// reserve выделяет номер объекта, который будет записан позже
+func (w *pdfWriter) reserve() int {
+	w.offsets = append(w.offsets, 0)
+	return len(w.offsets)
+}
+
+func (w *pdfWriter) object(id int, body string) {
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n%s\nendobj\n", id, body)
+}
+
+// stream записывает поток, сжатый FlateDecode. extra — дополнительные ключи словаря
+func (w *pdfWriter) stream(id int, data []byte, extra string) error {
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	if _, err := zw.Write(data); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	w.offsets[id-1] = w.buf.Len()
+	fmt.Fprintf(&w.buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode%s >>\nstream\n", id, compressed.Len(), extra)
+	w.buf.Write(compressed.Bytes())
+	w.buf.WriteString("\nendstream\nendobj\n")
+	return nil
+}
+
+// Bytes возвращает готовый документ
+func (d *pdfDocument) Bytes() ([]byte, error) {
+	w := &pdfWriter{}
+	w.buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	catalog := w.reserve()
+	pages := w.reserve()
+
+	var resources strings.Builder
+	resources.WriteString("<< /Font <<")
+	for _, f := range d.fonts {
+		id, err := d.writeFont(w, f)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Fprintf(&resources, " /%s %d 0 R", f.resource, id)
+	}
+	resources.WriteString(" >> >>")
+
+	kids := make([]string, 0, len(d.pages))
+	for _, p := range d.pages {
+		page, content := w.reserve(), w.reserve()
+		w.object(page, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+			pages, pageWidth, pageHeight, resources.String(), content))
+		if err := w.stream(content, p.content.Bytes(), ""); err != nil {
+			return nil, err
+		}
+		kids = append(kids, fmt.Sprintf("%d 0 R", page))
+	}
+	w.object(pages, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(kids)))
+	w.object(catalog, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pages))
+
+	xref := w.buf.Len()
+	fmt.Fprintf(&w.buf, "xref\n0 %d\n0000000000 65535 f \n", len(w.offsets)+1)
+	for _, offset := range w.offsets {
+		fmt.Fprintf(&w.buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&w.buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(w.offsets)+1, catalog, xref)
+	return w.buf.Bytes(), nil
+}
+
+// writeFont записывает составной шрифт Type0 с потомком CIDFontType2 и возвращает номер его объекта.
+// Вызывается после отрисовки страниц, когда известны все использованные глифы.
+func (d *pdfDocument) writeFont(w *pdfWriter, f *pdfFont) (int, error) {
+	type0, cid, descriptor, file, toUnicode := w.reserve(), w.reserve(), w.reserve(), w.reserve(), w.reserve()
+
+	glyphs := make([]sfnt.GlyphIndex, 0, len(f.widths))
+	for g := range f.widths {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	var widths strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, " %d [%d]", g, f.widths[g])
+	}
+
+	metrics, err := f.font.Metrics(&f.buf, fixed.I(1000), font.HintingNone)
+	if err != nil {
+		return 0, err
+	}
+	bounds, err := f.font.Bounds(&f.buf, fixed.I(1000), font.HintingNone)
+	if err != nil {
+		return 0, err
+	}
+
+	w.object(type0, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		f.name, cid, toUnicode))
+	w.object(cid, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s ] >>",
+		f.name, descriptor, widths.String()))
+	w.object(descriptor, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		f.name, bounds.Min.X.Round(), -bounds.Max.Y.Round(), bounds.Max.X.Round(), -bounds.Min.Y.Round(),
+		metrics.Ascent.Round(), -metrics.Descent.Round(), metrics.CapHeight.Round(), file))
+	if err := w.stream(file, f.data, fmt.Sprintf(" /Length1 %d", len(f.data))); err != nil {
+		return 0, err
+	}
+	if err := w.stream(toUnicode, f.toUnicode(glyphs), ""); err != nil {
+		return 0, err
+	}
+	return type0, nil
+}
+
+// toUnicode строит CMap соответствия глифов символам
+func (f *pdfFont) toUnicode(glyphs []sfnt.GlyphIndex) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	b.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	b.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	b.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+
+	var mapped []sfnt.GlyphIndex
+	for _, g := range glyphs {
+		if _, ok := f.runes[g]; ok {
+			mapped = append(mapped, g)
+		}
+	}
+	// В одном блоке bfchar допускается не больше 100 записей
+	for start := 0; start < len(mapped); start += 100 {
+		end := min(start+100, len(mapped))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, g := range mapped[start:end] {
+			fmt.Fprintf(&b, "<%04X> <", uint16(g))
+			for _, u := range utf16.Encode([]rune{f.runes[g]}) {
+				fmt.Fprintf(&b, "%04X", u)
+			}
+			b.WriteString(">\n")
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+)
+
+// chargedMonthsCTE раскладывает подписки по месяцам периода [$1, $2] с теми же правилами, что и расчет суммы:
+// пропускаются месяцы пробного периода и приостановки, цена берется из истории цен
+// и уменьшается на действующие в месяце скидки (как NetPriceAt, но не меньше нуля),
+// monthly — цена в пересчете на месяц, charge — списание, если на месяц приходится период оплаты.
+const chargedMonthsCTE = `
+WITH months AS (
+	SELECT generate_series($1::date, $2::date, interval '1 month')::date AS month
+),
+billed AS (
+	SELECT m.month, s.id, s.service_name, COALESCE(s.catalog_id::text, s.service_name) AS service_key,
+		GREATEST(COALESCE(p.price, s.price) - dc.reduction, 0) AS price,
+		CASE s.billing_cycle WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END AS cycle,
+		COALESCE(s.trial_end_date + interval '1 month', s.start_date)::date AS anchor
+	FROM months m
+	JOIN subscription s
+	  ON s.start_date <= m.month
+	 AND (s.end_date IS NULL OR s.end_date >= m.month)
+	 AND (s.trial_end_date IS NULL OR s.trial_end_date < m.month)
+	LEFT JOIN LATERAL (
+		SELECT sp.price FROM subscription_price sp
+		WHERE sp.subscription_id = s.id AND sp.effective_from <= m.month
+		ORDER BY sp.effective_from DESC
+		LIMIT 1
+	) p ON true
+	CROSS JOIN LATERAL (
+		SELECT COALESCE(sum(CASE d.type
+			WHEN 'percentage' THEN COALESCE(p.price, s.price) * d.value / 100
+			ELSE d.value END), 0) AS reduction
+		FROM subscription_discount d
+		WHERE d.subscription_id = s.id
+		  AND date_trunc('month', d.start_date) <= m.month
+		  AND (d.end_date IS NULL OR date_trunc('month', d.end_date) >= m.month)
+	) dc
+	LEFT JOIN LATERAL (
+		SELECT c.to_status FROM subscription_status_change c
+		WHERE c.subscription_id = s.id AND c.effective_date <= m.month
+		ORDER BY c.effective_date DESC, c.changed_at DESC
+		LIMIT 1
+	) st ON true
+	WHERE st.to_status IS DISTINCT FROM 'paused'
+),
+charged AS (
+	SELECT *,
+		price::numeric / cycle AS monthly,
+		CASE WHEN ((EXTRACT(YEAR FROM month) - EXTRACT(YEAR FROM anchor)) * 12
+			+ EXTRACT(MONTH FROM month) - EXTRACT(MONTH FROM anchor))::int % cycle = 0
+			THEN price ELSE 0 END AS charge
+	FROM billed
+)
+`
+
+// AnalyticsMonths считает MRR, списания, число действующих, новых и завершившихся подписок по месяцам
+func (sub *pgxRepository) AnalyticsMonths(ctx context.Context, from, to time.Time) ([]model.AnalyticsMonth, error) {
+	query := chargedMonthsCTE + `
+	SELECT m.month,
+		COALESCE(round(sum(c.monthly)), 0)::int,
+		COALESCE(sum(c.charge), 0)::int,
+		count(c.id)::int,
+		(SELECT count(*) FROM subscription s WHERE date_trunc('month', s.start_date) = m.month)::int,
+		(SELECT count(*) FROM subscription s WHERE date_trunc('month', s.end_date) = m.month)::int
+	FROM months m
+	LEFT JOIN charged c ON c.month = m.month
+	GROUP BY m.month
+	ORDER BY m.month
+	`
+	rows, err := sub.db.Query(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	months := []model.AnalyticsMonth{}
+	for rows.Next() {
+		var m model.AnalyticsMonth
+		var month time.Time
+		if err := rows.Scan(&month, &m.MRR, &m.Spend, &m.Active, &m.New, &m.Churned); err != nil {
+			return nil, err
+		}
+		m.Month = model.CustomDate{Time: month}
+		months = append(months, m)
+	}
+	return months, rows.Err()
+}
+
+// TopServices возвращает limit сервисов с наибольшими списаниями за период.
+// Подписки, привязанные к справочнику, группируются по записи справочника, остальные — по названию
+func (sub *pgxRepository) TopServices(ctx context.Context, from, to time.Time, limit int) ([]model.ServiceSpend, error) {
+	query := chargedMonthsCTE + `
+	SELECT max(service_name) AS service_name, sum(charge)::int AS total, count(DISTINCT id)::int
+	FROM charged
+	GROUP BY service_key
+	HAVING sum(charge) > 0
+	ORDER BY total DESC, service_name
+	LIMIT $3
+	`
+	rows, err := sub.db.Query(ctx, query, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	services := []model.ServiceSpend{}
+	for rows.Next() {
+		var s model.ServiceSpend
+		if err := rows.Scan(&s.ServiceName, &s.Total, &s.Subscriptions); err != nil {
+			return nil, err
+		}
+		services = append(services, s)
+	}
+	return services, rows.Err()
+}
+
+// AverageLifetime считает среднюю длительность в месяцах подписок, завершившихся в периоде
+func (sub *pgxRepository) AverageLifetime(ctx context.Context, from, to time.Time) (model.Lifetime, error) {
+	query := `
+	SELECT
+		COALESCE(avg((EXTRACT(YEAR FROM end_date) - EXTRACT(YEAR FROM start_date)) * 12
+			+ EXTRACT(MONTH FROM end_date) - EXTRACT(MONTH FROM start_date) + 1), 0)::float8,
+		count(*)::int
+	FROM subscription
+	WHERE end_date BETWEEN $1 AND $2
+	`
+	var l model.Lifetime
+	if err := sub.db.QueryRow(ctx, query, from, to).Scan(&l.AverageMonths, &l.Ended); err != nil {
+		return model.Lifetime{}, err
+	}
+	return l, nil
+}
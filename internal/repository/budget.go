@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"subscription/internal/model"
+)
+
+func (sub *pgxRepository) CreateBudget(ctx context.Context, b model.Budget) error {
+	query := `
+		INSERT INTO budget (id, user_id, service_name, category, monthly_limit)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := sub.db.Exec(ctx, query, b.ID, b.UserId, b.ServiceName, b.Category, b.MonthlyLimit)
+	return err
+}
+
+func (sub *pgxRepository) GetBudget(ctx context.Context, id string) (model.Budget, error) {
+	query := `
+	SELECT id, user_id, service_name, category, monthly_limit
+	FROM budget
+	WHERE id=$1
+	`
+	var b model.Budget
+	if err := sub.db.QueryRow(ctx, query, id).Scan(&b.ID, &b.UserId, &b.ServiceName, &b.Category, &b.MonthlyLimit); err != nil {
+		return model.Budget{}, err
+	}
+	return b, nil
+}
+
+// ListBudgets возвращает бюджеты пользователя или все бюджеты, если userId пустой
+func (sub *pgxRepository) ListBudgets(ctx context.Context, userId string) ([]model.Budget, error) {
+	query := `
+	SELECT id, user_id, service_name, category, monthly_limit
+	FROM budget
+	WHERE user_id::text = $1 OR $1 = ''
+	`
+	rows, err := sub.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []model.Budget{}
+	for rows.Next() {
+		var b model.Budget
+		if err := rows.Scan(&b.ID, &b.UserId, &b.ServiceName, &b.Category, &b.MonthlyLimit); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, rows.Err()
+}
+
+func (sub *pgxRepository) UpdateBudget(ctx context.Context, b model.Budget) error {
+	query := `
+		UPDATE budget
+		SET service_name=$1, category=$2, monthly_limit=$3
+		WHERE id=$4
+	`
+	cmd, err := sub.db.Exec(ctx, query, b.ServiceName, b.Category, b.MonthlyLimit, b.ID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("budget not found")
+	}
+	return nil
+}
+
+func (sub *pgxRepository) DeleteBudget(ctx context.Context, id string) error {
+	_, err := sub.db.Exec(ctx, `DELETE FROM budget WHERE id=$1`, id)
+	return err
+}
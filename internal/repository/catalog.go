@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"subscription/internal/model"
+)
+
+const catalogColumns = `id, name, aliases, default_price, category, website`
+
+func (sub *pgxRepository) CreateCatalogEntry(ctx context.Context, c model.CatalogEntry) error {
+	query := `
+		INSERT INTO catalog_service (` + catalogColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	_, err := sub.db.Exec(ctx, query, c.ID, c.Name, nonNilStrings(c.Aliases), c.DefaultPrice, c.Category, c.Website)
+	return err
+}
+
+func (sub *pgxRepository) GetCatalogEntry(ctx context.Context, id string) (model.CatalogEntry, error) {
+	query := `
+	SELECT ` + catalogColumns + `
+	FROM catalog_service
+	WHERE id=$1
+	`
+	var c model.CatalogEntry
+	if err := sub.db.QueryRow(ctx, query, id).Scan(&c.ID, &c.Name, &c.Aliases, &c.DefaultPrice, &c.Category, &c.Website); err != nil {
+		return model.CatalogEntry{}, err
+	}
+	return c, nil
+}
+
+func (sub *pgxRepository) ListCatalog(ctx context.Context) ([]model.CatalogEntry, error) {
+	query := `
+	SELECT ` + catalogColumns + `
+	FROM catalog_service
+	ORDER BY name
+	`
+	rows, err := sub.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	catalog := []model.CatalogEntry{}
+	for rows.Next() {
+		var c model.CatalogEntry
+		if err := rows.Scan(&c.ID, &c.Name, &c.Aliases, &c.DefaultPrice, &c.Category, &c.Website); err != nil {
+			return nil, err
+		}
+		catalog = append(catalog, c)
+	}
+	return catalog, rows.Err()
+}
+
+// UpdateCatalogEntry изменяет запись справочника и переименовывает привязанные к ней подписки
+func (sub *pgxRepository) UpdateCatalogEntry(ctx context.Context, c model.CatalogEntry) error {
+	query := `
+		UPDATE catalog_service
+		SET name=$1, aliases=$2, default_price=$3, category=$4, website=$5
+		WHERE id=$6
+	`
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, query, c.Name, nonNilStrings(c.Aliases), c.DefaultPrice, c.Category, c.Website, c.ID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("catalog entry not found")
+	}
+	if _, err := tx.Exec(ctx,
+		`UPDATE subscription SET service_name = $1 WHERE catalog_id = $2 AND service_name <> $1`,
+		c.Name, c.ID); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (sub *pgxRepository) DeleteCatalogEntry(ctx context.Context, id string) error {
+	_, err := sub.db.Exec(ctx, `DELETE FROM catalog_service WHERE id=$1`, id)
+	return err
+}
+
+// ListUnlinkedServiceNames возвращает названия сервисов подписок, не привязанных к справочнику
+func (sub *pgxRepository) ListUnlinkedServiceNames(ctx context.Context) ([]string, error) {
+	rows, err := sub.db.Query(ctx, `SELECT DISTINCT service_name FROM subscription WHERE catalog_id IS NULL ORDER BY service_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
+
+// LinkCatalog привязывает к записи справочника не привязанные подписки с названиями names:
+// название заменяется каноническим, пустая категория берется из справочника.
+// Возвращает число привязанных подписок
+func (sub *pgxRepository) LinkCatalog(ctx context.Context, c model.CatalogEntry, names []string) (int, error) {
+	query := `
+		UPDATE subscription
+		SET catalog_id = $1, service_name = $2, category = CASE WHEN category = '' THEN $3 ELSE category END
+		WHERE catalog_id IS NULL AND service_name = ANY($4)
+	`
+	cmd, err := sub.db.Exec(ctx, query, c.ID, c.Name, c.Category, names)
+	if err != nil {
+		return 0, err
+	}
+	return int(cmd.RowsAffected()), nil
+}
+
+// nonNilStrings заменяет nil на пустой срез, чтобы не писать NULL в NOT NULL массив
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// insertTags привязывает теги к подписке, создавая отсутствующие в справочнике
+func insertTags(ctx context.Context, tx pgx.Tx, subscriptionID string, tags []string) error {
+	upsert := `
+		INSERT INTO tag (name) VALUES ($1)
+		ON CONFLICT (name) DO UPDATE SET name = EXCLUDED.name
+		RETURNING id
+	`
+	link := `
+		INSERT INTO subscription_tag (subscription_id, tag_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	for _, name := range tags {
+		var tagID int
+		if err := tx.QueryRow(ctx, upsert, name).Scan(&tagID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, link, subscriptionID, tagID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadTags загружает теги для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadTags(ctx context.Context, ids []string) (map[string][]string, error) {
+	tags := make(map[string][]string)
+	if len(ids) == 0 {
+		return tags, nil
+	}
+
+	query := `
+	SELECT st.subscription_id::text, t.name
+	FROM subscription_tag st
+	JOIN tag t ON t.id = st.tag_id
+	WHERE st.subscription_id::text = ANY($1)
+	ORDER BY t.name
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subID, name string
+		if err := rows.Scan(&subID, &name); err != nil {
+			return nil, err
+		}
+		tags[subID] = append(tags[subID], name)
+	}
+	return tags, rows.Err()
+}
+
+// loadRelations подгружает участников, теги, скидки, напоминания, статистику использования, историю цен и статусов для списка подписок
+func (sub *pgxRepository) loadRelations(ctx context.Context, subs []model.Subscription) error {
+	ids := make([]string, 0, len(subs))
+	for _, s := range subs {
+		ids = append(ids, s.ID)
+	}
+
+	members, err := sub.loadMembers(ctx, ids)
+	if err != nil {
+		return err
+	}
+	tags, err := sub.loadTags(ctx, ids)
+	if err != nil {
+		return err
+	}
+	prices, err := sub.loadPrices(ctx, ids)
+	if err != nil {
+		return err
+	}
+	history, err := sub.loadStatusHistory(ctx, ids)
+	if err != nil {
+		return err
+	}
+	discounts, err := sub.loadDiscounts(ctx, ids)
+	if err != nil {
+		return err
+	}
+	reminders, err := sub.loadReminders(ctx, ids)
+	if err != nil {
+		return err
+	}
+	usage, err := sub.loadUsage(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for i := range subs {
+		subs[i].Members = members[subs[i].ID]
+		subs[i].Tags = tags[subs[i].ID]
+		subs[i].Prices = prices[subs[i].ID]
+		subs[i].StatusHistory = history[subs[i].ID]
+		subs[i].Discounts = discounts[subs[i].ID]
+		subs[i].Reminders = reminders[subs[i].ID]
+		subs[i].Usage = usage[subs[i].ID]
+	}
+	return nil
+}
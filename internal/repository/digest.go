@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (sub *pgxRepository) CreateDigestSetting(ctx context.Context, d model.DigestSetting) error {
+	query := `
+		INSERT INTO digest_setting (id, user_id, period, channel, target)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := sub.db.Exec(ctx, query, d.ID, d.UserId, d.Period, d.Channel, d.Target)
+	return err
+}
+
+// ListDigestSettings возвращает настройки дайджестов пользователя или все, если userId пустой
+func (sub *pgxRepository) ListDigestSettings(ctx context.Context, userId string) ([]model.DigestSetting, error) {
+	query := `
+	SELECT id, user_id::text, period, channel, target
+	FROM digest_setting
+	WHERE user_id::text = $1 OR $1 = ''
+	ORDER BY user_id, period, id
+	`
+	rows, err := sub.db.Query(ctx, query, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	settings := []model.DigestSetting{}
+	for rows.Next() {
+		var d model.DigestSetting
+		if err := rows.Scan(&d.ID, &d.UserId, &d.Period, &d.Channel, &d.Target); err != nil {
+			return nil, err
+		}
+		settings = append(settings, d)
+	}
+	return settings, rows.Err()
+}
+
+// DeleteDigestSetting удаляет настройку дайджеста пользователя, если ее нет — возвращает pgx.ErrNoRows
+func (sub *pgxRepository) DeleteDigestSetting(ctx context.Context, userId, id string) error {
+	cmd, err := sub.db.Exec(ctx,
+		`DELETE FROM digest_setting WHERE id=$1 AND user_id=$2`, id, userId)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ClaimDigest отмечает дайджест за период отправленным.
+// Возвращает false, если он уже был отмечен раньше.
+func (sub *pgxRepository) ClaimDigest(ctx context.Context, settingID string, periodStart time.Time) (bool, error) {
+	cmd, err := sub.db.Exec(ctx, `
+		INSERT INTO digest_sent (setting_id, period_start)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, settingID, periodStart)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// ReleaseDigest снимает отметку об отправке, чтобы дайджест отправился при следующем запуске
+func (sub *pgxRepository) ReleaseDigest(ctx context.Context, settingID string, periodStart time.Time) error {
+	_, err := sub.db.Exec(ctx,
+		`DELETE FROM digest_sent WHERE setting_id=$1 AND period_start=$2`, settingID, periodStart)
+	return err
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (sub *pgxRepository) CreateDiscount(ctx context.Context, d model.Discount) error {
+	query := `
+		INSERT INTO subscription_discount (id, subscription_id, type, value, start_date, end_date, code)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := sub.db.Exec(ctx, query,
+		d.ID,
+		d.SubscriptionID,
+		d.Type,
+		d.Value,
+		d.StartDate.Time,
+		nullableDate(d.EndDate),
+		d.Code)
+	return err
+}
+
+// DeleteDiscount удаляет скидку подписки, если скидки нет — возвращает pgx.ErrNoRows
+func (sub *pgxRepository) DeleteDiscount(ctx context.Context, subscriptionID, id string) error {
+	cmd, err := sub.db.Exec(ctx,
+		`DELETE FROM subscription_discount WHERE id=$1 AND subscription_id=$2`, id, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// loadDiscounts загружает скидки для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadDiscounts(ctx context.Context, ids []string) (map[string][]model.Discount, error) {
+	discounts := make(map[string][]model.Discount)
+	if len(ids) == 0 {
+		return discounts, nil
+	}
+
+	query := `
+	SELECT id, subscription_id::text, type, value, start_date, end_date, code
+	FROM subscription_discount
+	WHERE subscription_id::text = ANY($1)
+	ORDER BY start_date, id
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d model.Discount
+		var start time.Time
+		var end *time.Time
+		if err := rows.Scan(&d.ID, &d.SubscriptionID, &d.Type, &d.Value, &start, &end, &d.Code); err != nil {
+			return nil, err
+		}
+		d.StartDate = model.CustomDate{Time: start}
+		if end != nil {
+			d.EndDate = &model.CustomDate{Time: *end}
+		}
+		discounts[d.SubscriptionID] = append(discounts[d.SubscriptionID], d)
+	}
+	return discounts, rows.Err()
+}
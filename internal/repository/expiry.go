@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+)
+
+// ListExpired возвращает еще не истекшие подписки, в том числе отмененные,
+// последний месяц которых закончился до месяца before
+func (sub *pgxRepository) ListExpired(ctx context.Context, before time.Time) ([]model.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscription
+	WHERE status <> 'expired'
+	  AND end_date < $1
+	`
+	return sub.querySubscriptions(ctx, query, before)
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// insertMembers сохраняет участников совместной подписки в рамках транзакции
+func insertMembers(ctx context.Context, tx pgx.Tx, subscriptionID string, members []model.Member) error {
+	query := `
+		INSERT INTO subscription_member (subscription_id, user_id, share)
+		VALUES ($1, $2, $3)
+	`
+	for _, m := range members {
+		if _, err := tx.Exec(ctx, query, subscriptionID, m.UserId, m.Share); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadMembers загружает участников для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadMembers(ctx context.Context, ids []string) (map[string][]model.Member, error) {
+	members := make(map[string][]model.Member)
+	if len(ids) == 0 {
+		return members, nil
+	}
+
+	query := `
+	SELECT subscription_id::text, user_id::text, share
+	FROM subscription_member
+	WHERE subscription_id::text = ANY($1)
+	ORDER BY user_id
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subID string
+		var m model.Member
+		if err := rows.Scan(&subID, &m.UserId, &m.Share); err != nil {
+			return nil, err
+		}
+		members[subID] = append(members[subID], m)
+	}
+	return members, rows.Err()
+}
+
+// CreateHousehold добавляет домохозяйство вместе с его участниками
+func (sub *pgxRepository) CreateHousehold(ctx context.Context, h model.Household) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `INSERT INTO household (id, name) VALUES ($1, $2)`, h.ID, h.Name); err != nil {
+		return err
+	}
+	for _, userID := range h.Members {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO household_member (household_id, user_id) VALUES ($1, $2)`,
+			h.ID, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (sub *pgxRepository) GetHousehold(ctx context.Context, id string) (model.Household, error) {
+	var h model.Household
+	if err := sub.db.QueryRow(ctx,
+		`SELECT id, name FROM household WHERE id=$1`, id).Scan(&h.ID, &h.Name); err != nil {
+		return model.Household{}, err
+	}
+
+	rows, err := sub.db.Query(ctx,
+		`SELECT user_id::text FROM household_member WHERE household_id=$1 ORDER BY user_id`, id)
+	if err != nil {
+		return model.Household{}, err
+	}
+	defer rows.Close()
+
+	h.Members = []string{}
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
+			return model.Household{}, err
+		}
+		h.Members = append(h.Members, userID)
+	}
+	return h, rows.Err()
+}
+
+// ListHouseholdSubscriptions возвращает совместные подписки домохозяйства за период
+func (sub *pgxRepository) ListHouseholdSubscriptions(ctx context.Context, householdID string, from, to time.Time) ([]model.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscription
+	WHERE household_id = $1
+	  AND start_date <= $3
+	  AND (end_date IS NULL OR end_date >= $2)
+	`
+	return sub.querySubscriptions(ctx, query, householdID, from, to)
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"subscription/internal/model"
+)
+
+// TryJobLock захватывает сессионную advisory-блокировку задачи на отдельном соединении.
+// Блокировка живет, пока соединение не возвращено в пул, поэтому соединение удерживается до unlock.
+func (sub *pgxRepository) TryJobLock(ctx context.Context, name string) (func(), bool, error) {
+	conn, err := sub.db.Acquire(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := "job:" + name
+	var ok bool
+	if err := conn.QueryRow(ctx, `SELECT pg_try_advisory_lock(hashtext($1))`, key).Scan(&ok); err != nil {
+		conn.Release()
+		return nil, false, err
+	}
+	if !ok {
+		conn.Release()
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := conn.Exec(context.Background(), `SELECT pg_advisory_unlock(hashtext($1))`, key); err != nil {
+			// Закрытое соединение не вернется в пул, и Postgres снимет блокировку сам
+			log.Printf("failed to release job lock %s: %v", name, err)
+			conn.Conn().Close(context.Background())
+		}
+		conn.Release()
+	}
+	return unlock, true, nil
+}
+
+const jobRunColumns = `name, schedule, last_started_at, last_finished_at, last_status, last_result, last_error, next_run_at`
+
+func (sub *pgxRepository) GetJobRun(ctx context.Context, name string) (model.JobRun, error) {
+	var r model.JobRun
+	err := sub.db.QueryRow(ctx, `SELECT `+jobRunColumns+` FROM job_run WHERE name=$1`, name).Scan(
+		&r.Name, &r.Schedule, &r.LastStartedAt, &r.LastFinishedAt, &r.LastStatus, &r.LastResult, &r.LastError, &r.NextRunAt)
+	if err != nil {
+		return model.JobRun{}, err
+	}
+	return r, nil
+}
+
+func (sub *pgxRepository) SaveJobRun(ctx context.Context, r model.JobRun) error {
+	query := `
+		INSERT INTO job_run (` + jobRunColumns + `)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (name) DO UPDATE SET
+			schedule = EXCLUDED.schedule,
+			last_started_at = EXCLUDED.last_started_at,
+			last_finished_at = EXCLUDED.last_finished_at,
+			last_status = EXCLUDED.last_status,
+			last_result = EXCLUDED.last_result,
+			last_error = EXCLUDED.last_error,
+			next_run_at = EXCLUDED.next_run_at
+	`
+	_, err := sub.db.Exec(ctx, query,
+		r.Name,
+		r.Schedule,
+		r.LastStartedAt,
+		r.LastFinishedAt,
+		r.LastStatus,
+		r.LastResult,
+		r.LastError,
+		r.NextRunAt)
+	return err
+}
+
+func (sub *pgxRepository) ListJobRuns(ctx context.Context) ([]model.JobRun, error) {
+	rows, err := sub.db.Query(ctx, `SELECT `+jobRunColumns+` FROM job_run ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	runs := []model.JobRun{}
+	for rows.Next() {
+		var r model.JobRun
+		if err := rows.Scan(&r.Name, &r.Schedule, &r.LastStartedAt, &r.LastFinishedAt, &r.LastStatus, &r.LastResult, &r.LastError, &r.NextRunAt); err != nil {
+			return nil, err
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"subscription/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetJournalAccounts возвращает счета пользователя для проводок, ok=false если они не настроены
+func (sub *pgxRepository) GetJournalAccounts(ctx context.Context, userId string) (model.JournalAccounts, bool, error) {
+	query := `
+	SELECT user_id::text, default_account, funding_account, category_accounts
+	FROM journal_account
+	WHERE user_id::text = $1
+	`
+	var a model.JournalAccounts
+	err := sub.db.QueryRow(ctx, query, userId).Scan(&a.UserId, &a.Default, &a.Funding, &a.Categories)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return model.JournalAccounts{}, false, nil
+	}
+	if err != nil {
+		return model.JournalAccounts{}, false, err
+	}
+	return a, true, nil
+}
+
+// SaveJournalAccounts сохраняет счета пользователя, заменяя прежние
+func (sub *pgxRepository) SaveJournalAccounts(ctx context.Context, a model.JournalAccounts) error {
+	query := `
+		INSERT INTO journal_account (user_id, default_account, funding_account, category_accounts)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id) DO UPDATE SET
+			default_account = EXCLUDED.default_account,
+			funding_account = EXCLUDED.funding_account,
+			category_accounts = EXCLUDED.category_accounts
+	`
+	_, err := sub.db.Exec(ctx, query, a.UserId, a.Default, a.Funding, a.Categories)
+	return err
+}
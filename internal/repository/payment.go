@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+const paymentColumns = `id, subscription_id::text, amount, currency, paid_at, method, reference`
+
+func (sub *pgxRepository) CreatePayment(ctx context.Context, p model.Payment) error {
+	query := `
+		INSERT INTO payment (id, subscription_id, amount, currency, paid_at, method, reference)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	_, err := sub.db.Exec(ctx, query, p.ID, p.SubscriptionID, p.Amount, p.Currency, p.PaidAt, p.Method, p.Reference)
+	return err
+}
+
+// GetPayment возвращает платеж подписки, если его нет — pgx.ErrNoRows
+func (sub *pgxRepository) GetPayment(ctx context.Context, subscriptionID, id string) (model.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payment WHERE id=$1 AND subscription_id=$2`
+	return scanPayment(sub.db.QueryRow(ctx, query, id, subscriptionID))
+}
+
+// ListPayments возвращает платежи подписки по возрастанию даты
+func (sub *pgxRepository) ListPayments(ctx context.Context, subscriptionID string) ([]model.Payment, error) {
+	query := `SELECT ` + paymentColumns + ` FROM payment WHERE subscription_id=$1 ORDER BY paid_at, id`
+	return sub.queryPayments(ctx, query, subscriptionID)
+}
+
+// ListPaymentsForPeriod возвращает платежи набора подписок с from по to включительно
+func (sub *pgxRepository) ListPaymentsForPeriod(ctx context.Context, ids []string, from, to time.Time) ([]model.Payment, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	query := `
+	SELECT ` + paymentColumns + `
+	FROM payment
+	WHERE subscription_id::text = ANY($1) AND paid_at BETWEEN $2 AND $3
+	ORDER BY paid_at, id
+	`
+	return sub.queryPayments(ctx, query, ids, from, to)
+}
+
+// UpdatePayment обновляет платеж подписки, если его нет — возвращает pgx.ErrNoRows
+func (sub *pgxRepository) UpdatePayment(ctx context.Context, p model.Payment) error {
+	query := `
+		UPDATE payment
+		SET amount=$1, currency=$2, paid_at=$3, method=$4, reference=$5
+		WHERE id=$6 AND subscription_id=$7
+	`
+	cmd, err := sub.db.Exec(ctx, query, p.Amount, p.Currency, p.PaidAt, p.Method, p.Reference, p.ID, p.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// DeletePayment удаляет платеж подписки, если его нет — возвращает pgx.ErrNoRows
+func (sub *pgxRepository) DeletePayment(ctx context.Context, subscriptionID, id string) error {
+	cmd, err := sub.db.Exec(ctx, `DELETE FROM payment WHERE id=$1 AND subscription_id=$2`, id, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (sub *pgxRepository) queryPayments(ctx context.Context, query string, args ...any) ([]model.Payment, error) {
+	rows, err := sub.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []model.Payment
+	for rows.Next() {
+		p, err := scanPayment(rows)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, p)
+	}
+	return payments, rows.Err()
+}
+
+func scanPayment(row pgx.Row) (model.Payment, error) {
+	var p model.Payment
+	err := row.Scan(&p.ID, &p.SubscriptionID, &p.Amount, &p.Currency, &p.PaidAt, &p.Method, &p.Reference)
+	return p, err
+}
@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (sub *pgxRepository) CreatePlan(ctx context.Context, p model.Plan) error {
+	query := `
+		INSERT INTO plan (id, catalog_id, name, price)
+		VALUES ($1, $2, $3, $4)
+	`
+	_, err := sub.db.Exec(ctx, query, p.ID, p.CatalogID, p.Name, p.Price)
+	return err
+}
+
+func (sub *pgxRepository) GetPlan(ctx context.Context, id string) (model.Plan, error) {
+	query := `
+	SELECT id, catalog_id, name, price
+	FROM plan
+	WHERE id=$1
+	`
+	var p model.Plan
+	if err := sub.db.QueryRow(ctx, query, id).Scan(&p.ID, &p.CatalogID, &p.Name, &p.Price); err != nil {
+		return model.Plan{}, err
+	}
+	return p, nil
+}
+
+func (sub *pgxRepository) ListPlans(ctx context.Context, catalogID string) ([]model.Plan, error) {
+	query := `
+	SELECT id, catalog_id, name, price
+	FROM plan
+	WHERE catalog_id=$1
+	ORDER BY price
+	`
+	rows, err := sub.db.Query(ctx, query, catalogID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plans := []model.Plan{}
+	for rows.Next() {
+		var p model.Plan
+		if err := rows.Scan(&p.ID, &p.CatalogID, &p.Name, &p.Price); err != nil {
+			return nil, err
+		}
+		plans = append(plans, p)
+	}
+	return plans, rows.Err()
+}
+
+// ChangePlan сохраняет новый тариф, историю цен подписки и запись о смене тарифа
+func (sub *pgxRepository) ChangePlan(ctx context.Context, s model.Subscription, change model.PlanChange) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`UPDATE subscription SET plan_id=$1, price=$2 WHERE id=$3`,
+		s.PlanID, s.Price, s.ID); err != nil {
+		return err
+	}
+	if err := replacePrices(ctx, tx, s.ID, s.Prices); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO subscription_plan_change
+		(id, subscription_id, from_plan_id, to_plan_id, old_price, new_price, effective_date, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	if _, err := tx.Exec(ctx, query,
+		change.ID,
+		change.SubscriptionID,
+		change.FromPlanID,
+		change.ToPlanID,
+		change.OldPrice,
+		change.NewPrice,
+		change.EffectiveDate.Time,
+		change.ChangedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+func (sub *pgxRepository) ListPlanChanges(ctx context.Context, subscriptionID string) ([]model.PlanChange, error) {
+	query := `
+	SELECT id, subscription_id, from_plan_id, to_plan_id, old_price, new_price, effective_date, changed_at
+	FROM subscription_plan_change
+	WHERE subscription_id=$1
+	ORDER BY effective_date, changed_at
+	`
+	rows, err := sub.db.Query(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []model.PlanChange{}
+	for rows.Next() {
+		var c model.PlanChange
+		var effective time.Time
+		if err := rows.Scan(&c.ID, &c.SubscriptionID, &c.FromPlanID, &c.ToPlanID,
+			&c.OldPrice, &c.NewPrice, &effective, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		c.EffectiveDate = model.CustomDate{Time: effective}
+		c.Direction = model.PlanDirection(c.OldPrice, c.NewPrice)
+		changes = append(changes, c)
+	}
+	return changes, rows.Err()
+}
+
+// SavePrices сохраняет текущую цену и историю цен подписки
+func (sub *pgxRepository) SavePrices(ctx context.Context, s model.Subscription) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx, `UPDATE subscription SET price=$1 WHERE id=$2`, s.Price, s.ID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("subscription not found")
+	}
+	if err := replacePrices(ctx, tx, s.ID, s.Prices); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// replacePrices перезаписывает историю цен подписки в рамках транзакции
+func replacePrices(ctx context.Context, tx pgx.Tx, subscriptionID string, prices []model.PricePoint) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM subscription_price WHERE subscription_id=$1`, subscriptionID); err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO subscription_price (subscription_id, effective_from, price)
+		VALUES ($1, $2, $3)
+	`
+	for _, p := range prices {
+		if _, err := tx.Exec(ctx, query, subscriptionID, p.EffectiveFrom.Time, p.Price); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadPrices загружает историю цен для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadPrices(ctx context.Context, ids []string) (map[string][]model.PricePoint, error) {
+	prices := make(map[string][]model.PricePoint)
+	if len(ids) == 0 {
+		return prices, nil
+	}
+
+	query := `
+	SELECT subscription_id::text, effective_from, price
+	FROM subscription_price
+	WHERE subscription_id::text = ANY($1)
+	ORDER BY effective_from
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var subID string
+		var effective time.Time
+		var p model.PricePoint
+		if err := rows.Scan(&subID, &effective, &p.Price); err != nil {
+			return nil, err
+		}
+		p.EffectiveFrom = model.CustomDate{Time: effective}
+		prices[subID] = append(prices[subID], p)
+	}
+	return prices, rows.Err()
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+)
+
+// ReplaceProposals заменяет неподтвержденные предложения пользователя новыми
+func (sub *pgxRepository) ReplaceProposals(ctx context.Context, userId string, proposals []model.Proposal) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM subscription_proposal WHERE user_id::text = $1 AND status = $2`,
+		userId, model.ProposalPending); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO subscription_proposal
+			(id, user_id, merchant, service_name, price, billing_cycle, start_date, last_charged, occurrences, matched, status)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	for _, p := range proposals {
+		if _, err := tx.Exec(ctx, query,
+			p.ID, p.UserId, p.Merchant, p.ServiceName, p.Price, p.BillingCycle,
+			p.StartDate.Time, p.LastCharged, p.Occurrences, p.Matched, p.Status); err != nil {
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ListProposals возвращает неподтвержденные предложения пользователя
+func (sub *pgxRepository) ListProposals(ctx context.Context, userId string) ([]model.Proposal, error) {
+	query := `
+	SELECT id, user_id::text, merchant, service_name, price, billing_cycle, start_date, last_charged, occurrences, matched, status
+	FROM subscription_proposal
+	WHERE user_id::text = $1 AND status = $2
+	ORDER BY service_name, id
+	`
+	rows, err := sub.db.Query(ctx, query, userId, model.ProposalPending)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var proposals []model.Proposal
+	for rows.Next() {
+		var p model.Proposal
+		var start time.Time
+		if err := rows.Scan(&p.ID, &p.UserId, &p.Merchant, &p.ServiceName, &p.Price, &p.BillingCycle,
+			&start, &p.LastCharged, &p.Occurrences, &p.Matched, &p.Status); err != nil {
+			return nil, err
+		}
+		p.StartDate = model.CustomDate{Time: start}
+		proposals = append(proposals, p)
+	}
+	return proposals, rows.Err()
+}
+
+// ConfirmProposals в одной транзакции создает подписки и отмечает соответствующие предложения подтвержденными.
+// Если какое-то предложение уже не ожидает подтверждения, ничего не сохраняется.
+func (sub *pgxRepository) ConfirmProposals(ctx context.Context, proposals []model.Proposal, subscriptions []model.Subscription) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	for i, p := range proposals {
+		if err := insertSubscription(ctx, tx, subscriptions[i]); err != nil {
+			return err
+		}
+		tag, err := tx.Exec(ctx,
+			`UPDATE subscription_proposal SET status = $1, subscription_id = $2 WHERE id = $3 AND status = $4`,
+			model.ProposalConfirmed, subscriptions[i].ID, p.ID, model.ProposalPending)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return model.ErrProposalNotPending
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ListServiceNames возвращает названия сервисов всех подписок
+func (sub *pgxRepository) ListServiceNames(ctx context.Context) ([]string, error) {
+	rows, err := sub.db.Query(ctx, `SELECT DISTINCT service_name FROM subscription ORDER BY service_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	return names, rows.Err()
+}
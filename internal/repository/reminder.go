@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (sub *pgxRepository) CreateReminder(ctx context.Context, r model.ReminderSetting) error {
+	query := `
+		INSERT INTO reminder_setting (id, subscription_id, channel, target, lead_days)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	_, err := sub.db.Exec(ctx, query, r.ID, r.SubscriptionID, r.Channel, r.Target, r.LeadDays)
+	return err
+}
+
+// DeleteReminder удаляет настройку напоминаний подписки, если ее нет — возвращает pgx.ErrNoRows
+func (sub *pgxRepository) DeleteReminder(ctx context.Context, subscriptionID, id string) error {
+	cmd, err := sub.db.Exec(ctx,
+		`DELETE FROM reminder_setting WHERE id=$1 AND subscription_id=$2`, id, subscriptionID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// loadReminders загружает настройки напоминаний для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadReminders(ctx context.Context, ids []string) (map[string][]model.ReminderSetting, error) {
+	reminders := make(map[string][]model.ReminderSetting)
+	if len(ids) == 0 {
+		return reminders, nil
+	}
+
+	query := `
+	SELECT id, subscription_id::text, channel, target, lead_days
+	FROM reminder_setting
+	WHERE subscription_id::text = ANY($1)
+	ORDER BY channel, id
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r model.ReminderSetting
+		if err := rows.Scan(&r.ID, &r.SubscriptionID, &r.Channel, &r.Target, &r.LeadDays); err != nil {
+			return nil, err
+		}
+		reminders[r.SubscriptionID] = append(reminders[r.SubscriptionID], r)
+	}
+	return reminders, rows.Err()
+}
+
+// ListRemindable возвращает действующие подписки, у которых настроены напоминания
+func (sub *pgxRepository) ListRemindable(ctx context.Context) ([]model.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscription s
+	WHERE status NOT IN ('cancelled', 'expired')
+	  AND EXISTS (SELECT 1 FROM reminder_setting r WHERE r.subscription_id = s.id)
+	`
+	return sub.querySubscriptions(ctx, query)
+}
+
+// ClaimReminder отмечает напоминание отправленным.
+// Возвращает false, если оно уже было отмечено раньше и отправлять его не нужно.
+func (sub *pgxRepository) ClaimReminder(ctx context.Context, r model.Reminder) (bool, error) {
+	cmd, err := sub.db.Exec(ctx, `
+		INSERT INTO reminder_sent (setting_id, kind, due_date)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`, r.SettingID, r.Kind, r.DueDate)
+	if err != nil {
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// ReleaseReminder снимает отметку об отправке, чтобы напоминание отправилось при следующем запуске
+func (sub *pgxRepository) ReleaseReminder(ctx context.Context, r model.Reminder) error {
+	_, err := sub.db.Exec(ctx,
+		`DELETE FROM reminder_sent WHERE setting_id=$1 AND kind=$2 AND due_date=$3`,
+		r.SettingID, r.Kind, r.DueDate)
+	return err
+}
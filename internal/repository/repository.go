@@ -7,6 +7,7 @@ import (
 	"subscription/internal/model"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -14,6 +15,26 @@ type pgxRepository struct {
 	db *pgxpool.Pool
 }
 
+const subscriptionColumns = `id, user_id, service_name, price, start_date, end_date, household_id, split_type, category, catalog_id, plan_id, status, trial_end_date, billing_cycle`
+
+// filterCondition отбирает подписки по model.Filter, параметры $1-$5 передает filterArgs.
+// Пользователь совпадает, если он владелец или участник совместной подписки.
+// Подписки, привязанные к справочнику, отбираются по catalog_id, остальные — по названию.
+const filterCondition = `
+	($1 = '' OR s.user_id::text = $1 OR EXISTS (
+		SELECT 1 FROM subscription_member m
+		WHERE m.subscription_id = s.id AND m.user_id::text = $1))
+	AND ($2 = '' OR s.catalog_id::text = $5 OR (s.catalog_id IS NULL AND s.service_name = $2))
+	AND (s.category = $3 OR $3 = '')
+	AND ($4 = '' OR EXISTS (
+		SELECT 1 FROM subscription_tag st JOIN tag t ON t.id = st.tag_id
+		WHERE st.subscription_id = s.id AND t.name = $4))
+`
+
+func filterArgs(f model.Filter) []any {
+	return []any{f.UserId, f.ServiceName, f.Category, f.Tag, f.CatalogID}
+}
+
 func nullableDate(cd *model.CustomDate) interface{} {
 	if cd == nil {
 		return nil
@@ -21,6 +42,52 @@ func nullableDate(cd *model.CustomDate) interface{} {
 	return cd.Time
 }
 
+// scanSubscription читает одну строку, выбранную с колонками subscriptionColumns
+func scanSubscription(row pgx.Row) (model.Subscription, error) {
+	var s model.Subscription
+	var startDate time.Time
+	var endDate, trialEnd sql.NullTime
+
+	if err := row.Scan(&s.ID, &s.UserId, &s.ServiceName, &s.Price, &startDate, &endDate, &s.HouseholdID, &s.SplitType, &s.Category, &s.CatalogID, &s.PlanID, &s.Status, &trialEnd, &s.BillingCycle); err != nil {
+		return model.Subscription{}, err
+	}
+	s.StartDate = model.CustomDate{Time: startDate}
+	if endDate.Valid {
+		s.EndDate = &model.CustomDate{Time: endDate.Time}
+	}
+	if trialEnd.Valid {
+		s.TrialEnd = &model.CustomDate{Time: trialEnd.Time}
+	}
+	return s, nil
+}
+
+// querySubscriptions выполняет запрос со списком подписок и подгружает их участников
+func (sub *pgxRepository) querySubscriptions(ctx context.Context, query string, args ...any) ([]model.Subscription, error) {
+	rows, err := sub.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subs []model.Subscription
+
+	for rows.Next() {
+		s, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := sub.loadRelations(ctx, subs); err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
 func NewPgxRepository(db *pgxpool.Pool) *pgxRepository {
 	return &pgxRepository{
 		db: db,
@@ -29,13 +96,27 @@ func NewPgxRepository(db *pgxpool.Pool) *pgxRepository {
 
 // AddSub используется для добавления нашей подписки в хранилище(Store)
 func (sub *pgxRepository) Create(ctx context.Context, subscription model.Subscription) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertSubscription(ctx, tx, subscription); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// insertSubscription записывает подписку с участниками и тегами в рамках транзакции
+func insertSubscription(ctx context.Context, tx pgx.Tx, subscription model.Subscription) error {
 	query := `
 		INSERT 
 		INTO subscription 
-		(id, user_id, service_name, price, start_date, end_date)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		(id, user_id, service_name, price, start_date, end_date, household_id, split_type, category, catalog_id, plan_id, status, trial_end_date, billing_cycle)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
 	`
-	_, err := sub.db.Exec(
+	_, err := tx.Exec(
 		ctx,
 		query,
 		subscription.ID,
@@ -43,61 +124,53 @@ func (sub *pgxRepository) Create(ctx context.Context, subscription model.Subscri
 		subscription.ServiceName,
 		subscription.Price,
 		subscription.StartDate.Time,
-		nullableDate(subscription.EndDate))
-	return err
+		nullableDate(subscription.EndDate),
+		subscription.HouseholdID,
+		subscription.SplitType,
+		subscription.Category,
+		subscription.CatalogID,
+		subscription.PlanID,
+		subscription.Status,
+		nullableDate(subscription.TrialEnd),
+		subscription.BillingCycle)
+	if err != nil {
+		return err
+	}
+	if err := insertMembers(ctx, tx, subscription.ID, subscription.Members); err != nil {
+		return err
+	}
+	return insertTags(ctx, tx, subscription.ID, subscription.Tags)
 }
 
 func (sub *pgxRepository) GetByID(ctx context.Context, Id string) (model.Subscription, error) {
 
 	query := `
-	SELECT id, user_id, service_name, price, start_date, end_date
+	SELECT ` + subscriptionColumns + `
 	FROM subscription
 	WHERE id=$1
 	`
-	var s model.Subscription
-	var startDate time.Time
-	var endDate sql.NullTime
-
-	if err := sub.db.QueryRow(ctx, query, Id).Scan(&s.ID, &s.UserId, &s.ServiceName, &s.Price, &startDate, &endDate); err != nil {
+	s, err := scanSubscription(sub.db.QueryRow(ctx, query, Id))
+	if err != nil {
 		return model.Subscription{}, err
 	}
-	s.StartDate = model.CustomDate{Time: startDate}
-	if endDate.Valid {
-		s.EndDate = &model.CustomDate{Time: endDate.Time}
+
+	subs := []model.Subscription{s}
+	if err := sub.loadRelations(ctx, subs); err != nil {
+		return model.Subscription{}, err
 	}
 
-	return s, nil
+	return subs[0], nil
 }
 
-func (sub *pgxRepository) GetAll(ctx context.Context) ([]model.Subscription, error) {
+func (sub *pgxRepository) GetAll(ctx context.Context, filter model.Filter) ([]model.Subscription, error) {
 
 	query := `
-	SELECT id, user_id, service_name, price, start_date, end_date
-	FROM subscription 
+	SELECT ` + subscriptionColumns + `
+	FROM subscription s
+	WHERE ` + filterCondition + `
+	  AND ($6 OR s.status <> 'expired')
 	`
-	rows, err := sub.db.Query(ctx, query)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	var subs []model.Subscription
-	var startDate time.Time
-	var endDate sql.NullTime
-
-	for rows.Next() {
-		var sub model.Subscription
-		if err := rows.Scan(&sub.ID, &sub.UserId, &sub.ServiceName, &sub.Price, &startDate, &endDate); err != nil {
-			return nil, err
-		}
-		sub.StartDate = model.CustomDate{Time: startDate}
-		if endDate.Valid {
-			sub.EndDate = &model.CustomDate{Time: endDate.Time}
-		}
-
-		subs = append(subs, sub)
-	}
-	return subs, nil
+	return sub.querySubscriptions(ctx, query, append(filterArgs(filter), filter.IncludeExpired)...)
 }
 
 // Удаление записи из нашей базы данных
@@ -115,16 +188,28 @@ func (sub *pgxRepository) Update(ctx context.Context, id string, newSub model.Su
 
 	query := `
 		UPDATE subscription 
-		SET service_name=$1, price=$2, start_date=$3
-		WHERE id=$4
+		SET service_name=$1, price=$2, start_date=$3, household_id=$4, split_type=$5, category=$6, catalog_id=$7, plan_id=$8, billing_cycle=$9
+		WHERE id=$10
 		RETURNING id
 	`
-	cmd, err := sub.db.Exec(
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(
 		ctx,
 		query,
 		newSub.ServiceName,
 		newSub.Price,
 		newSub.StartDate.Time,
+		newSub.HouseholdID,
+		newSub.SplitType,
+		newSub.Category,
+		newSub.CatalogID,
+		newSub.PlanID,
+		newSub.BillingCycle,
 		id)
 	if err != nil {
 		return err
@@ -132,25 +217,35 @@ func (sub *pgxRepository) Update(ctx context.Context, id string, newSub model.Su
 	if cmd.RowsAffected() == 0 {
 		return errors.New("subscription not found")
 	}
-	return nil
+
+	if _, err := tx.Exec(ctx, `DELETE FROM subscription_member WHERE subscription_id=$1`, id); err != nil {
+		return err
+	}
+	if err := insertMembers(ctx, tx, id, newSub.Members); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM subscription_tag WHERE subscription_id=$1`, id); err != nil {
+		return err
+	}
+	if err := insertTags(ctx, tx, id, newSub.Tags); err != nil {
+		return err
+	}
+	if err := replacePrices(ctx, tx, id, newSub.Prices); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
 }
 
-// Метод для подсчета суммарной стоимости всех подписок за выбранный период
-func (sub *pgxRepository) SumForPeriod(ctx context.Context, userId, serviceName string, from, to time.Time) (int, error) {
+// ListForPeriod возвращает подписки, подходящие под фильтр и действующие хотя бы один месяц периода
+func (sub *pgxRepository) ListForPeriod(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.Subscription, error) {
 
 	query := `
-        SELECT SUM(price) 
-        FROM subscription
-        WHERE (user_id = $1 OR $1 = '')
-          AND (service_name = $2 OR $2 = '')
-          AND start_date >= $3 AND start_date <= $4
+        SELECT ` + subscriptionColumns + `
+        FROM subscription s
+        WHERE ` + filterCondition + `
+          AND s.start_date <= $7
+          AND (s.end_date IS NULL OR s.end_date >= $6)
     `
-	var sum int
-	err := sub.db.QueryRow(
-		ctx,
-		query,
-		userId,
-		serviceName,
-		from, to).Scan(&sum)
-	return sum, err
+	args := append(filterArgs(filter), from, to)
+	return sub.querySubscriptions(ctx, query, args...)
 }
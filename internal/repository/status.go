@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"subscription/internal/model"
+	"time"
+)
+
+// ChangeStatus сохраняет новый статус и дату окончания подписки вместе с записью о смене статуса
+func (sub *pgxRepository) ChangeStatus(ctx context.Context, s model.Subscription, change model.StatusChange) error {
+	tx, err := sub.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx,
+		`UPDATE subscription SET status=$1, end_date=$2 WHERE id=$3`,
+		s.Status, nullableDate(s.EndDate), s.ID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return errors.New("subscription not found")
+	}
+
+	query := `
+		INSERT INTO subscription_status_change
+		(id, subscription_id, from_status, to_status, reason, effective_date, changed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+	if _, err := tx.Exec(ctx, query,
+		change.ID,
+		change.SubscriptionID,
+		change.From,
+		change.To,
+		change.Reason,
+		change.EffectiveDate.Time,
+		change.ChangedAt); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// loadStatusHistory загружает историю статусов для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadStatusHistory(ctx context.Context, ids []string) (map[string][]model.StatusChange, error) {
+	history := make(map[string][]model.StatusChange)
+	if len(ids) == 0 {
+		return history, nil
+	}
+
+	query := `
+	SELECT id, subscription_id::text, from_status, to_status, reason, effective_date, changed_at
+	FROM subscription_status_change
+	WHERE subscription_id::text = ANY($1)
+	ORDER BY effective_date, changed_at
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c model.StatusChange
+		var effective time.Time
+		if err := rows.Scan(&c.ID, &c.SubscriptionID, &c.From, &c.To, &c.Reason, &effective, &c.ChangedAt); err != nil {
+			return nil, err
+		}
+		c.EffectiveDate = model.CustomDate{Time: effective}
+		history[c.SubscriptionID] = append(history[c.SubscriptionID], c)
+	}
+	return history, rows.Err()
+}
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+)
+
+// ListTrialsEnding возвращает действующие подписки, пробный период которых заканчивается в месяцах [from, to]
+func (sub *pgxRepository) ListTrialsEnding(ctx context.Context, userId string, from, to time.Time) ([]model.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscription
+	WHERE trial_end_date BETWEEN $2 AND $3
+	  AND status NOT IN ('cancelled', 'expired')
+	  AND (user_id::text = $1 OR $1 = '')
+	ORDER BY trial_end_date
+	`
+	return sub.querySubscriptions(ctx, query, userId, from, to)
+}
+
+// ListTrialsEnded возвращает подписки в статусе trial, пробный период которых закончился до месяца before
+func (sub *pgxRepository) ListTrialsEnded(ctx context.Context, before time.Time) ([]model.Subscription, error) {
+	query := `
+	SELECT ` + subscriptionColumns + `
+	FROM subscription
+	WHERE status = 'trial'
+	  AND trial_end_date < $1
+	`
+	return sub.querySubscriptions(ctx, query, before)
+}
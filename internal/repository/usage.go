@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"subscription/internal/model"
+)
+
+// RecordUsage добавляет использования к статистике подписки и возвращает обновленную статистику
+func (sub *pgxRepository) RecordUsage(ctx context.Context, u model.Usage) (model.Usage, error) {
+	query := `
+		INSERT INTO subscription_usage (subscription_id, first_used_at, last_used_at, use_count)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (subscription_id) DO UPDATE SET
+			first_used_at = LEAST(subscription_usage.first_used_at, EXCLUDED.first_used_at),
+			last_used_at = GREATEST(subscription_usage.last_used_at, EXCLUDED.last_used_at),
+			use_count = subscription_usage.use_count + EXCLUDED.use_count
+		RETURNING subscription_id::text, first_used_at, last_used_at, use_count
+	`
+	var result model.Usage
+	err := sub.db.QueryRow(ctx, query, u.SubscriptionID, u.FirstUsedAt, u.LastUsedAt, u.Count).
+		Scan(&result.SubscriptionID, &result.FirstUsedAt, &result.LastUsedAt, &result.Count)
+	return result, err
+}
+
+// loadUsage загружает статистику использования для набора подписок, ключ — ID подписки
+func (sub *pgxRepository) loadUsage(ctx context.Context, ids []string) (map[string]*model.Usage, error) {
+	usage := make(map[string]*model.Usage)
+	if len(ids) == 0 {
+		return usage, nil
+	}
+
+	query := `
+	SELECT subscription_id::text, first_used_at, last_used_at, use_count
+	FROM subscription_usage
+	WHERE subscription_id::text = ANY($1)
+	`
+	rows, err := sub.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u model.Usage
+		if err := rows.Scan(&u.SubscriptionID, &u.FirstUsedAt, &u.LastUsedAt, &u.Count); err != nil {
+			return nil, err
+		}
+		usage[u.SubscriptionID] = &u
+	}
+	return usage, rows.Err()
+}
@@ -0,0 +1,156 @@
+// Package scheduler выполняет фоновые задачи по cron-расписанию.
+// Состояние задач хранится в базе, а advisory-блокировка Postgres гарантирует,
+// что при нескольких репликах каждый запуск выполняет только одна из них.
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"subscription/internal/model"
+	"time"
+)
+
+// Func тело задачи, возвращает краткое описание результата
+type Func func(ctx context.Context) (string, error)
+
+// Store хранит состояние задач и выдает блокировки на их запуск
+type Store interface {
+	// TryJobLock пытается захватить блокировку задачи, не дожидаясь ее освобождения.
+	// Если блокировка получена, ее нужно отпустить вызовом unlock.
+	TryJobLock(ctx context.Context, name string) (unlock func(), ok bool, err error)
+	// GetJobRun возвращает состояние задачи или sql.ErrNoRows, если задача еще не сохранялась
+	GetJobRun(ctx context.Context, name string) (model.JobRun, error)
+	SaveJobRun(ctx context.Context, run model.JobRun) error
+}
+
+type job struct {
+	name     string
+	spec     string
+	schedule Schedule
+	run      Func
+}
+
+type Scheduler struct {
+	store Store
+	jobs  []job
+	tick  time.Duration
+	now   func() time.Time
+}
+
+func NewScheduler(store Store) *Scheduler {
+	return &Scheduler{
+		store: store,
+		tick:  time.Minute,
+		now:   func() time.Time { return time.Now().UTC() },
+	}
+}
+
+// Register добавляет задачу с cron-расписанием spec.
+// Расписание, которое никогда не срабатывает (например, 31 февраля), отклоняется.
+func (s *Scheduler) Register(name, spec string, run Func) error {
+	schedule, err := ParseSchedule(spec)
+	if err != nil {
+		return err
+	}
+	if schedule.Next(s.now()).IsZero() {
+		return fmt.Errorf("schedule %q never fires", spec)
+	}
+	for _, j := range s.jobs {
+		if j.name == name {
+			return fmt.Errorf("job %q is already registered", name)
+		}
+	}
+	s.jobs = append(s.jobs, job{name: name, spec: spec, schedule: schedule, run: run})
+	return nil
+}
+
+// Start запускает проверку расписания раз в минуту до отмены ctx
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.tick)
+		defer ticker.Stop()
+
+		s.RunDue(ctx)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.RunDue(ctx)
+			}
+		}
+	}()
+	log.Printf("scheduler started: jobs=%d", len(s.jobs))
+}
+
+// RunDue выполняет задачи, время запуска которых наступило
+func (s *Scheduler) RunDue(ctx context.Context) {
+	for _, j := range s.jobs {
+		if err := s.runIfDue(ctx, j); err != nil {
+			log.Printf("scheduler job %s: %v", j.name, err)
+		}
+	}
+}
+
+// runIfDue запускает задачу под блокировкой. Состояние перечитывается уже после захвата
+// блокировки, поэтому реплика, получившая ее позже, увидит новый next_run_at и пропустит запуск.
+func (s *Scheduler) runIfDue(ctx context.Context, j job) error {
+	unlock, ok, err := s.store.TryJobLock(ctx, j.name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return nil
+	}
+	defer unlock()
+
+	now := s.now()
+	run, err := s.store.GetJobRun(ctx, j.name)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		// Первый запуск планируется на ближайшее время по расписанию
+		return s.store.SaveJobRun(ctx, model.JobRun{Name: j.name, Schedule: j.spec, NextRunAt: j.schedule.Next(now)})
+	case err != nil:
+		return err
+	}
+
+	if run.Schedule != j.spec {
+		run.Schedule = j.spec
+		run.NextRunAt = j.schedule.Next(now)
+		return s.store.SaveJobRun(ctx, run)
+	}
+	if run.NextRunAt.IsZero() || now.Before(run.NextRunAt) {
+		return nil
+	}
+
+	started := now
+	result, runErr := s.execute(ctx, j)
+	finished := s.now()
+
+	run.LastStartedAt = &started
+	run.LastFinishedAt = &finished
+	run.LastResult = result
+	run.LastStatus = model.JobSucceeded
+	run.LastError = ""
+	if runErr != nil {
+		run.LastStatus = model.JobFailed
+		run.LastError = runErr.Error()
+	}
+	run.NextRunAt = j.schedule.Next(finished)
+
+	log.Printf("scheduler job %s %s in %s: %s", j.name, run.LastStatus, finished.Sub(started), result)
+	return s.store.SaveJobRun(ctx, run)
+}
+
+// execute выполняет задачу, превращая панику в ошибку запуска
+func (s *Scheduler) execute(ctx context.Context, j job) (result string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.run(ctx)
+}
@@ -0,0 +1,127 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"subscription/internal/model"
+	"testing"
+	"time"
+)
+
+type fakeStore struct {
+	runs   map[string]model.JobRun
+	locked map[string]bool
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{runs: map[string]model.JobRun{}, locked: map[string]bool{}}
+}
+
+func (f *fakeStore) TryJobLock(ctx context.Context, name string) (func(), bool, error) {
+	if f.locked[name] {
+		return nil, false, nil
+	}
+	f.locked[name] = true
+	return func() { f.locked[name] = false }, true, nil
+}
+func (f *fakeStore) GetJobRun(ctx context.Context, name string) (model.JobRun, error) {
+	r, ok := f.runs[name]
+	if !ok {
+		return model.JobRun{}, sql.ErrNoRows
+	}
+	return r, nil
+}
+func (f *fakeStore) SaveJobRun(ctx context.Context, r model.JobRun) error {
+	f.runs[r.Name] = r
+	return nil
+}
+
+func TestScheduleNext_Unit(t *testing.T) {
+
+	from := time.Date(2025, 1, 31, 10, 17, 30, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 1, 31, 10, 30, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 31, 11, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2025, 2, 3, 9, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 6 29 2 *", time.Date(2028, 2, 29, 6, 30, 0, 0, time.UTC)},
+		{"0 0 15 * 7", time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.spec, err)
+		}
+		if got := s.Next(from); !got.Equal(tt.want) {
+			t.Errorf("%s: expected %s, got %s", tt.spec, tt.want, got)
+		}
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("%s: expected error", spec)
+		}
+	}
+}
+
+func TestRunDue_Unit(t *testing.T) {
+
+	store := newFakeStore()
+	s := NewScheduler(store)
+	now := time.Date(2025, 1, 1, 10, 5, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	calls := 0
+	if err := s.Register("job", "@hourly", func(ctx context.Context) (string, error) {
+		calls++
+		return "done", errors.New("boom")
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	// Первая проверка только планирует запуск
+	s.RunDue(ctx)
+	if calls != 0 || !store.runs["job"].NextRunAt.Equal(time.Date(2025, 1, 1, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected job scheduled at 11:00 without running, calls=%d run=%+v", calls, store.runs["job"])
+	}
+
+	now = now.Add(time.Hour)
+	// Блокировка занята другой репликой
+	store.locked["job"] = true
+	s.RunDue(ctx)
+	if calls != 0 {
+		t.Fatalf("expected job skipped while locked, calls=%d", calls)
+	}
+
+	store.locked["job"] = false
+	s.RunDue(ctx)
+	s.RunDue(ctx)
+	run := store.runs["job"]
+	if calls != 1 {
+		t.Fatalf("expected job to run once, calls=%d", calls)
+	}
+	if run.LastStatus != model.JobFailed || run.LastError != "boom" || run.LastResult != "done" {
+		t.Fatalf("unexpected last run %+v", run)
+	}
+	if !run.NextRunAt.Equal(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected next run at 12:00, got %s", run.NextRunAt)
+	}
+}
+
+func TestRegisterNeverFires_Unit(t *testing.T) {
+
+	s := NewScheduler(newFakeStore())
+	noop := func(ctx context.Context) (string, error) { return "", nil }
+
+	if err := s.Register("february", "0 0 31 2 *", noop); err == nil {
+		t.Fatal("expected error for a schedule that never fires")
+	}
+	if err := s.Register("leap", "0 0 29 2 *", noop); err != nil {
+		t.Fatalf("expected leap day schedule to be accepted, got %v", err)
+	}
+}
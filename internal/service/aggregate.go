@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"math"
+	"slices"
+	"sort"
+	"subscription/internal/model"
+	"time"
+)
+
+// aggregateKey значения измерений одной группы
+type aggregateKey struct {
+	service  string
+	user     string
+	category string
+	month    time.Time
+}
+
+// Aggregate группирует списания за период по произвольному набору измерений
+// и считает для каждой группы запрошенные метрики.
+// Без измерений возвращается одна строка по всем списаниям.
+func (s *ServiceStore) Aggregate(ctx context.Context, filter model.Filter, from, to time.Time, groupBy []model.Dimension, metrics []model.Metric) ([]model.AggregateRow, error) {
+
+	subs, err := s.listForPeriod(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+	// В разрезе пользователей совместные подписки делятся между участниками, как в расчете суммы по пользователю
+	list := charges(subs, filter.UserId, from, to)
+	if filter.UserId == "" && slices.Contains(groupBy, model.DimUser) {
+		list = memberCharges(subs, from, to)
+	}
+	return aggregate(list, groupBy, metrics), nil
+}
+
+// aggregate группирует списания и считает метрики
+func aggregate(list []model.Charge, groupBy []model.Dimension, metrics []model.Metric) []model.AggregateRow {
+	type group struct {
+		sum   int
+		count int
+	}
+	groups := make(map[aggregateKey]*group)
+	for _, c := range list {
+		var key aggregateKey
+		for _, dim := range groupBy {
+			switch dim {
+			case model.DimService:
+				key.service = c.ServiceName
+			case model.DimUser:
+				key.user = c.UserId
+			case model.DimCategory:
+				key.category = c.Category
+			case model.DimMonth:
+				key.month = c.Month.Time
+			}
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = &group{}
+			groups[key] = g
+		}
+		g.sum += c.Amount
+		g.count++
+	}
+
+	keys := make([]aggregateKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if !a.month.Equal(b.month) {
+			return a.month.Before(b.month)
+		}
+		if a.user != b.user {
+			return a.user < b.user
+		}
+		if a.category != b.category {
+			return a.category < b.category
+		}
+		return a.service < b.service
+	})
+
+	rows := make([]model.AggregateRow, 0, len(keys))
+	for _, key := range keys {
+		g := groups[key]
+		row := model.AggregateRow{ServiceName: key.service, UserId: key.user, Category: key.category}
+		if slices.Contains(groupBy, model.DimMonth) {
+			row.Month = &model.CustomDate{Time: key.month}
+		}
+		for _, m := range metrics {
+			switch m {
+			case model.MetricSum:
+				sum := g.sum
+				row.Sum = &sum
+			case model.MetricCount:
+				count := g.count
+				row.Count = &count
+			case model.MetricAvg:
+				avg := math.Round(float64(g.sum)*100/float64(g.count)) / 100
+				row.Avg = &avg
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"math"
+	"subscription/internal/model"
+	"time"
+)
+
+// AnalyticsRepository считает показатели агрегатными SQL-запросами, не загружая подписки целиком
+type AnalyticsRepository interface {
+	AnalyticsMonths(ctx context.Context, from, to time.Time) ([]model.AnalyticsMonth, error)
+	TopServices(ctx context.Context, from, to time.Time, limit int) ([]model.ServiceSpend, error)
+	AverageLifetime(ctx context.Context, from, to time.Time) (model.Lifetime, error)
+}
+
+// Analytics собирает MRR/ARR, новые и завершившиеся подписки по месяцам,
+// среднюю длительность подписок и самые дорогие сервисы за период
+func (s *ServiceStore) Analytics(ctx context.Context, from, to time.Time, top int) (model.Analytics, error) {
+
+	from, to = model.MonthStart(from), model.MonthStart(to)
+
+	months, err := s.analyticsStore.AnalyticsMonths(ctx, from, to)
+	if err != nil {
+		return model.Analytics{}, err
+	}
+	services, err := s.analyticsStore.TopServices(ctx, from, to, top)
+	if err != nil {
+		return model.Analytics{}, err
+	}
+	lifetime, err := s.analyticsStore.AverageLifetime(ctx, from, to)
+	if err != nil {
+		return model.Analytics{}, err
+	}
+
+	for i := range months {
+		months[i].ARR = months[i].MRR * 12
+		if months[i].Active > 0 {
+			rate := float64(months[i].Churned) / float64(months[i].Active) * 100
+			months[i].ChurnRate = math.Round(rate*100) / 100
+		}
+	}
+	lifetime.AverageMonths = math.Round(lifetime.AverageMonths*10) / 10
+
+	result := model.Analytics{
+		From:        model.CustomDate{Time: from},
+		To:          model.CustomDate{Time: to},
+		Months:      months,
+		Lifetime:    lifetime,
+		TopServices: services,
+	}
+	if len(months) > 0 {
+		result.MRR = months[len(months)-1].MRR
+		result.ARR = months[len(months)-1].ARR
+	}
+	return result, nil
+}
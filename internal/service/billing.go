@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"subscription/internal/model"
+	"time"
+)
+
+// BillingStatement готовит выписку пользователя за период: по строке на подписку
+// с оплаченными месяцами и стоимостью доли пользователя с учетом скидок
+func (s *ServiceStore) BillingStatement(ctx context.Context, userId string, from, to time.Time) (model.BillingStatement, error) {
+
+	subs, err := s.listForPeriod(ctx, model.Filter{UserId: userId}, from, to)
+	if err != nil {
+		return model.BillingStatement{}, err
+	}
+
+	statement := model.BillingStatement{
+		UserId:      userId,
+		From:        model.MonthStart(from),
+		To:          model.MonthStart(to),
+		Currency:    model.BaseCurrency,
+		GeneratedAt: time.Now().UTC(),
+	}
+
+	lines := make(map[string]*model.BillingLine)
+	var order []string
+	for _, c := range charges(subs, userId, from, to) {
+		line, ok := lines[c.SubscriptionID]
+		if !ok {
+			line = &model.BillingLine{SubscriptionID: c.SubscriptionID, ServiceName: c.ServiceName, Category: c.Category}
+			lines[c.SubscriptionID] = line
+			order = append(order, c.SubscriptionID)
+		}
+		line.Months = append(line.Months, c.Month.Time)
+		line.Amount += c.Amount
+		statement.Total += c.Amount
+	}
+
+	statement.Lines = make([]model.BillingLine, 0, len(order))
+	for _, id := range order {
+		statement.Lines = append(statement.Lines, *lines[id])
+	}
+	sort.SliceStable(statement.Lines, func(i, j int) bool {
+		return statement.Lines[i].ServiceName < statement.Lines[j].ServiceName
+	})
+	return statement, nil
+}
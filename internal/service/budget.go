@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"log"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type BudgetRepository interface {
+	CreateBudget(ctx context.Context, b model.Budget) error
+	GetBudget(ctx context.Context, id string) (model.Budget, error)
+	ListBudgets(ctx context.Context, userId string) ([]model.Budget, error)
+	UpdateBudget(ctx context.Context, b model.Budget) error
+	DeleteBudget(ctx context.Context, id string) error
+}
+
+func (s *ServiceStore) CreateBudget(ctx context.Context, dto datatransfer.DTOBudget) (model.Budget, error) {
+
+	b := model.NewBudget(dto)
+	name, err := s.canonicalName(ctx, b.ServiceName)
+	if err != nil {
+		return model.Budget{}, err
+	}
+	b.ServiceName = name
+	if err := s.budgetStore.CreateBudget(ctx, b); err != nil {
+		return model.Budget{}, err
+	}
+	return b, nil
+}
+
+func (s *ServiceStore) GetBudget(ctx context.Context, id string) (model.Budget, error) {
+	return s.budgetStore.GetBudget(ctx, id)
+}
+
+func (s *ServiceStore) ListBudgets(ctx context.Context, userId string) ([]model.Budget, error) {
+	return s.budgetStore.ListBudgets(ctx, userId)
+}
+
+func (s *ServiceStore) UpdateBudget(ctx context.Context, id string, dto datatransfer.DTOBudget) (model.Budget, error) {
+
+	old, err := s.budgetStore.GetBudget(ctx, id)
+	if err != nil {
+		return model.Budget{}, err
+	}
+	name, err := s.canonicalName(ctx, dto.ServiceName)
+	if err != nil {
+		return model.Budget{}, err
+	}
+	updated := model.Budget{
+		ID:           old.ID,
+		UserId:       old.UserId,
+		ServiceName:  name,
+		Category:     model.NormalizeLabel(dto.Category),
+		MonthlyLimit: dto.MonthlyLimit,
+	}
+	if err := s.budgetStore.UpdateBudget(ctx, updated); err != nil {
+		return model.Budget{}, err
+	}
+	return updated, nil
+}
+
+func (s *ServiceStore) DeleteBudget(ctx context.Context, id string) error {
+	return s.budgetStore.DeleteBudget(ctx, id)
+}
+
+// BudgetStatus возвращает использование бюджета за текущий месяц
+func (s *ServiceStore) BudgetStatus(ctx context.Context, id string) (model.BudgetStatus, error) {
+
+	b, err := s.budgetStore.GetBudget(ctx, id)
+	if err != nil {
+		return model.BudgetStatus{}, err
+	}
+	return s.budgetStatus(ctx, b, model.MonthStart(time.Now()))
+}
+
+func (s *ServiceStore) budgetStatus(ctx context.Context, b model.Budget, month time.Time) (model.BudgetStatus, error) {
+
+	spent, err := s.Sum(ctx, b.Filter(), month, month)
+	if err != nil {
+		return model.BudgetStatus{}, err
+	}
+	return model.NewBudgetStatus(b, model.CustomDate{Time: month}, spent.Net), nil
+}
+
+// checkBudgets проверяет бюджеты всех участников подписки после её создания или изменения
+// и публикует событие, если именно это изменение вывело ожидаемые расходы за лимит.
+// before — подписка до изменения, nil для новой подписки.
+// Ошибки проверки не мешают сохранению подписки и только пишутся в лог.
+func (s *ServiceStore) checkBudgets(ctx context.Context, before *model.Subscription, after model.Subscription) {
+
+	// Ожидаемые расходы считаем за первый месяц, в котором подписка будет оплачиваться
+	month := model.MonthStart(time.Now())
+	if start := model.MonthStart(after.StartDate.Time); start.After(month) {
+		month = start
+	}
+
+	users := after.Shares()
+	if before != nil {
+		for userId, share := range before.Shares() {
+			users[userId] += share
+		}
+	}
+
+	for userId := range users {
+		budgets, err := s.budgetStore.ListBudgets(ctx, userId)
+		if err != nil {
+			log.Printf("failed to load budgets: user_id=%s: %v", userId, err)
+			continue
+		}
+		for _, b := range budgets {
+			if !b.Covers(after) && (before == nil || !b.Covers(*before)) {
+				continue
+			}
+			status, err := s.budgetStatus(ctx, b, month)
+			if err != nil {
+				log.Printf("failed to check budget: id=%s: %v", b.ID, err)
+				continue
+			}
+			// Расходы до изменения: вычитаем вклад новой версии подписки и добавляем вклад прежней
+			previous := status.Spent - budgetShare(&after, b, month) + budgetShare(before, b, month)
+			if !status.Exceeded || previous > b.MonthlyLimit {
+				continue
+			}
+			if err := s.events.Publish(ctx, model.NewEvent(model.EventBudgetExceeded, status)); err != nil {
+				log.Printf("failed to publish budget alert: id=%s: %v", b.ID, err)
+			}
+		}
+	}
+}
+
+// budgetShare доля владельца бюджета в списании подписки за месяц, если бюджет на неё распространяется
+func budgetShare(sub *model.Subscription, b model.Budget, month time.Time) int {
+	if sub == nil || !b.Covers(*sub) {
+		return 0
+	}
+	return total(charges([]model.Subscription{*sub}, b.UserId, month, month))
+}
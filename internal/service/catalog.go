@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+)
+
+type CatalogRepository interface {
+	CreateCatalogEntry(ctx context.Context, c model.CatalogEntry) error
+	GetCatalogEntry(ctx context.Context, id string) (model.CatalogEntry, error)
+	ListCatalog(ctx context.Context) ([]model.CatalogEntry, error)
+	UpdateCatalogEntry(ctx context.Context, c model.CatalogEntry) error
+	DeleteCatalogEntry(ctx context.Context, id string) error
+	ListUnlinkedServiceNames(ctx context.Context) ([]string, error)
+	LinkCatalog(ctx context.Context, c model.CatalogEntry, names []string) (int, error)
+}
+
+func (s *ServiceStore) CreateCatalogEntry(ctx context.Context, dto datatransfer.DTOCatalogEntry) (model.CatalogEntry, error) {
+
+	c := model.NewCatalogEntry(dto)
+	if err := s.catalogStore.CreateCatalogEntry(ctx, c); err != nil {
+		return model.CatalogEntry{}, err
+	}
+	if _, err := s.LinkCatalog(ctx); err != nil {
+		return model.CatalogEntry{}, err
+	}
+	return c, nil
+}
+
+func (s *ServiceStore) GetCatalogEntry(ctx context.Context, id string) (model.CatalogEntry, error) {
+	return s.catalogStore.GetCatalogEntry(ctx, id)
+}
+
+func (s *ServiceStore) ListCatalog(ctx context.Context) ([]model.CatalogEntry, error) {
+	return s.catalogStore.ListCatalog(ctx)
+}
+
+func (s *ServiceStore) UpdateCatalogEntry(ctx context.Context, id string, dto datatransfer.DTOCatalogEntry) (model.CatalogEntry, error) {
+
+	if _, err := s.catalogStore.GetCatalogEntry(ctx, id); err != nil {
+		return model.CatalogEntry{}, err
+	}
+	c := model.NewCatalogEntry(dto)
+	c.ID = id
+	if err := s.catalogStore.UpdateCatalogEntry(ctx, c); err != nil {
+		return model.CatalogEntry{}, err
+	}
+	if _, err := s.LinkCatalog(ctx); err != nil {
+		return model.CatalogEntry{}, err
+	}
+	return c, nil
+}
+
+func (s *ServiceStore) DeleteCatalogEntry(ctx context.Context, id string) error {
+	return s.catalogStore.DeleteCatalogEntry(ctx, id)
+}
+
+// LinkCatalog привязывает к справочнику подписки, созданные до появления подходящей записи:
+// каждое не привязанное название сопоставляется со справочником так же, как при создании подписки.
+// Возвращает число привязанных подписок
+func (s *ServiceStore) LinkCatalog(ctx context.Context) (int, error) {
+
+	catalog, err := s.catalogStore.ListCatalog(ctx)
+	if err != nil || len(catalog) == 0 {
+		return 0, err
+	}
+	names, err := s.catalogStore.ListUnlinkedServiceNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+	byEntry := make(map[string][]string)
+	entries := make(map[string]model.CatalogEntry)
+	for _, name := range names {
+		if entry, ok := model.ResolveService(catalog, name); ok {
+			byEntry[entry.ID] = append(byEntry[entry.ID], name)
+			entries[entry.ID] = entry
+		}
+	}
+
+	linked := 0
+	for id, names := range byEntry {
+		n, err := s.catalogStore.LinkCatalog(ctx, entries[id], names)
+		if err != nil {
+			return linked, err
+		}
+		linked += n
+	}
+	return linked, nil
+}
+
+// ResolveService сопоставляет свободное название сервиса со справочником
+func (s *ServiceStore) ResolveService(ctx context.Context, name string) (model.CatalogEntry, bool, error) {
+
+	catalog, err := s.catalogStore.ListCatalog(ctx)
+	if err != nil {
+		return model.CatalogEntry{}, false, err
+	}
+	entry, ok := model.ResolveService(catalog, name)
+	return entry, ok, nil
+}
+
+// applyCatalog заменяет название сервиса каноническим и привязывает подписку к справочнику.
+// Категория из справочника используется, если у подписки своя не указана.
+func (s *ServiceStore) applyCatalog(ctx context.Context, sub *model.Subscription) error {
+
+	entry, ok, err := s.ResolveService(ctx, sub.ServiceName)
+	if err != nil || !ok {
+		return err
+	}
+	sub.ServiceName = entry.Name
+	sub.CatalogID = &entry.ID
+	if sub.Category == "" {
+		sub.Category = entry.Category
+	}
+	return nil
+}
+
+// canonicalName возвращает каноническое название сервиса, если оно есть в справочнике
+func (s *ServiceStore) canonicalName(ctx context.Context, name string) (string, error) {
+
+	if name == "" {
+		return "", nil
+	}
+	entry, ok, err := s.ResolveService(ctx, name)
+	if err != nil || !ok {
+		return name, err
+	}
+	return entry.Name, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"subscription/internal/model"
+	"time"
+)
+
+// Compare сравнивает стоимость подписок за период [from, to] с периодом [prevFrom, prevTo]
+// и раскладывает изменение по сервисам.
+func (s *ServiceStore) Compare(ctx context.Context, filter model.Filter, from, to, prevFrom, prevTo time.Time) (model.Comparison, error) {
+
+	current, err := s.listForPeriod(ctx, filter, from, to)
+	if err != nil {
+		return model.Comparison{}, err
+	}
+	previous, err := s.listForPeriod(ctx, filter, prevFrom, prevTo)
+	if err != nil {
+		return model.Comparison{}, err
+	}
+
+	return compare(
+		charges(current, filter.UserId, from, to),
+		charges(previous, filter.UserId, prevFrom, prevTo),
+		from, to, prevFrom, prevTo,
+	), nil
+}
+
+// compare строит сравнение двух наборов списаний
+func compare(current, previous []model.Charge, from, to, prevFrom, prevTo time.Time) model.Comparison {
+	result := model.Comparison{
+		Current: model.PeriodTotal{
+			From:  model.CustomDate{Time: from},
+			To:    model.CustomDate{Time: to},
+			Total: totals(current),
+		},
+		Previous: model.PeriodTotal{
+			From:  model.CustomDate{Time: prevFrom},
+			To:    model.CustomDate{Time: prevTo},
+			Total: totals(previous),
+		},
+	}
+	result.Delta = result.Current.Total.Net - result.Previous.Total.Net
+	result.DeltaPercent = model.PercentChange(result.Previous.Total.Net, result.Current.Total.Net)
+
+	byService := make(map[string]*model.ServiceDelta)
+	service := func(name string) *model.ServiceDelta {
+		d, ok := byService[name]
+		if !ok {
+			d = &model.ServiceDelta{ServiceName: name}
+			byService[name] = d
+		}
+		return d
+	}
+	for _, c := range current {
+		service(c.ServiceName).Current += c.Amount
+	}
+	for _, c := range previous {
+		service(c.ServiceName).Previous += c.Amount
+	}
+
+	result.Services = make([]model.ServiceDelta, 0, len(byService))
+	for _, d := range byService {
+		d.Delta = d.Current - d.Previous
+		d.DeltaPercent = model.PercentChange(d.Previous, d.Current)
+		result.Services = append(result.Services, *d)
+	}
+	sort.Slice(result.Services, func(i, j int) bool {
+		a, b := abs(result.Services[i].Delta), abs(result.Services[j].Delta)
+		if a != b {
+			return a > b
+		}
+		return result.Services[i].ServiceName < result.Services[j].ServiceName
+	})
+	return result
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
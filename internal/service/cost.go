@@ -0,0 +1,75 @@
+package service
+
+import (
+	"subscription/internal/model"
+	"time"
+)
+
+// charges раскладывает подписки на ежемесячные списания за период.
+// Если указан пользователь, в каждое списание попадает только его доля,
+// иначе — полная стоимость, которую оплачивает владелец.
+func charges(subs []model.Subscription, userId string, from, to time.Time) []model.Charge {
+	var result []model.Charge
+	for _, sub := range subs {
+		for _, month := range sub.BilledMonths(from, to) {
+			gross := sub.PriceAt(month)
+			amount := sub.NetPriceAt(month)
+			payer := sub.UserId
+			if userId != "" {
+				gross = sub.SharesOf(gross)[userId]
+				amount = sub.SharesOf(amount)[userId]
+				payer = userId
+			}
+			if gross == 0 {
+				continue
+			}
+			result = append(result, model.Charge{
+				SubscriptionID: sub.ID,
+				ServiceName:    sub.ServiceName,
+				Category:       sub.Category,
+				UserId:         payer,
+				Month:          model.CustomDate{Time: month},
+				Gross:          gross,
+				Amount:         amount,
+			})
+		}
+	}
+	return result
+}
+
+// memberCharges раскладывает каждое списание на доли участников подписки,
+// чтобы совместные подписки не приписывались целиком владельцу
+func memberCharges(subs []model.Subscription, from, to time.Time) []model.Charge {
+	var result []model.Charge
+	for _, sub := range subs {
+		for _, c := range charges([]model.Subscription{sub}, "", from, to) {
+			gross, net := sub.SharesOf(c.Gross), sub.SharesOf(c.Amount)
+			for userId := range gross {
+				if gross[userId] == 0 {
+					continue
+				}
+				share := c
+				share.UserId = userId
+				share.Gross = gross[userId]
+				share.Amount = net[userId]
+				result = append(result, share)
+			}
+		}
+	}
+	return result
+}
+
+// total суммирует списания к оплате
+func total(list []model.Charge) int {
+	return totals(list).Net
+}
+
+// totals суммирует списания до и после скидок
+func totals(list []model.Charge) model.Totals {
+	var t model.Totals
+	for _, c := range list {
+		t.Gross += c.Gross
+		t.Net += c.Amount
+	}
+	return t
+}
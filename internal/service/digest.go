@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"subscription/internal/report"
+	"time"
+)
+
+type DigestRepository interface {
+	CreateDigestSetting(ctx context.Context, d model.DigestSetting) error
+	ListDigestSettings(ctx context.Context, userId string) ([]model.DigestSetting, error)
+	DeleteDigestSetting(ctx context.Context, userId, id string) error
+	ClaimDigest(ctx context.Context, settingID string, periodStart time.Time) (bool, error)
+	ReleaseDigest(ctx context.Context, settingID string, periodStart time.Time) error
+}
+
+// Digest собирает сводку расходов пользователя за период, в который попадает at.
+// Суммы считаются по списаниям, которые приходятся на дни периода.
+func (s *ServiceStore) Digest(ctx context.Context, userId string, period model.DigestPeriod, at time.Time) (model.Digest, error) {
+
+	from, to := period.PeriodBounds(at)
+	prevFrom, prevTo := period.PeriodBounds(from.AddDate(0, 0, -1))
+	nextFrom, nextTo := period.PeriodBounds(to.AddDate(0, 0, 1))
+
+	subs, err := s.listForPeriod(ctx, model.Filter{UserId: userId}, prevFrom, nextTo)
+	if err != nil {
+		return model.Digest{}, err
+	}
+
+	digest := model.Digest{
+		UserId:        userId,
+		Period:        period,
+		From:          from,
+		To:            to,
+		Active:        []model.Subscription{},
+		New:           []model.Subscription{},
+		Ended:         []model.Subscription{},
+		Total:         total(chargesBetween(subs, userId, from, to)),
+		PreviousTotal: total(chargesBetween(subs, userId, prevFrom, prevTo)),
+		Upcoming:      chargesBetween(subs, userId, nextFrom, nextTo),
+	}
+	digest.Change = digest.Total - digest.PreviousTotal
+	if digest.Upcoming == nil {
+		digest.Upcoming = []model.Charge{}
+	}
+
+	for _, sub := range subs {
+		if !sub.ActiveBetween(from, to) {
+			continue
+		}
+		digest.Active = append(digest.Active, sub)
+		if start := model.MonthStart(sub.StartDate.Time); !start.Before(from) {
+			digest.New = append(digest.New, sub)
+		}
+		if last, ok := sub.LastDay(); ok && !last.After(to) {
+			digest.Ended = append(digest.Ended, sub)
+		}
+	}
+	return digest, nil
+}
+
+// chargesBetween возвращает списания, дата которых попадает в дни [from, to]
+func chargesBetween(subs []model.Subscription, userId string, from, to time.Time) []model.Charge {
+	var result []model.Charge
+	for _, c := range charges(subs, userId, from, to) {
+		if !c.Month.Time.Before(from) && !c.Month.Time.After(to) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (s *ServiceStore) AddDigestSetting(ctx context.Context, userId string, dto datatransfer.DTODigestSetting) (model.DigestSetting, error) {
+
+	setting := model.NewDigestSetting(userId, dto)
+	if err := s.digestStore.CreateDigestSetting(ctx, setting); err != nil {
+		return model.DigestSetting{}, err
+	}
+	return setting, nil
+}
+
+func (s *ServiceStore) ListDigestSettings(ctx context.Context, userId string) ([]model.DigestSetting, error) {
+	return s.digestStore.ListDigestSettings(ctx, userId)
+}
+
+func (s *ServiceStore) DeleteDigestSetting(ctx context.Context, userId, id string) error {
+	return s.digestStore.DeleteDigestSetting(ctx, userId, id)
+}
+
+// SendDigests отправляет дайджесты за последний завершившийся период и возвращает число отправленных.
+// Как и напоминания, дайджест отмечается в базе до отправки и снимается с отметки при ошибке доставки.
+func (s *ServiceStore) SendDigests(ctx context.Context) (int, error) {
+
+	settings, err := s.digestStore.ListDigestSettings(ctx, "")
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, setting := range settings {
+		current, _ := setting.Period.PeriodBounds(time.Now())
+		ok, err := s.sendDigest(ctx, setting, current.AddDate(0, 0, -1))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("digest %s: %w", setting.ID, err))
+			continue
+		}
+		if ok {
+			sent++
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// sendDigest отправляет дайджест за период, в который попадает at, если он еще не отправлялся
+func (s *ServiceStore) sendDigest(ctx context.Context, setting model.DigestSetting, at time.Time) (bool, error) {
+
+	if _, ok := s.notifiers[setting.Channel]; !ok {
+		return false, fmt.Errorf("channel %s is not configured", setting.Channel)
+	}
+
+	digest, err := s.Digest(ctx, setting.UserId, setting.Period, at)
+	if err != nil {
+		return false, err
+	}
+	msg, err := report.DigestNotification(digest, setting)
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := s.digestStore.ClaimDigest(ctx, setting.ID, digest.From)
+	if err != nil || !claimed {
+		return false, err
+	}
+	if err := s.notify(ctx, msg); err != nil {
+		if releaseErr := s.digestStore.ReleaseDigest(ctx, setting.ID, digest.From); releaseErr != nil {
+			return false, errors.Join(err, releaseErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+)
+
+type DiscountRepository interface {
+	CreateDiscount(ctx context.Context, d model.Discount) error
+	DeleteDiscount(ctx context.Context, subscriptionID, id string) error
+}
+
+// AddDiscount прикрепляет к подписке скидку на ограниченный период.
+// Скидка уменьшает сумму к оплате, но не цену подписки.
+func (s *ServiceStore) AddDiscount(ctx context.Context, id string, dto datatransfer.DTODiscount) (model.Discount, error) {
+
+	if _, err := s.subscriptionStore.GetByID(ctx, id); err != nil {
+		return model.Discount{}, err
+	}
+
+	discount, err := model.NewDiscount(id, dto)
+	if err != nil {
+		return model.Discount{}, err
+	}
+	if err := s.discountStore.CreateDiscount(ctx, discount); err != nil {
+		return model.Discount{}, err
+	}
+	return discount, nil
+}
+
+func (s *ServiceStore) ListDiscounts(ctx context.Context, id string) ([]model.Discount, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Discounts == nil {
+		return []model.Discount{}, nil
+	}
+	return sub.Discounts, nil
+}
+
+func (s *ServiceStore) DeleteDiscount(ctx context.Context, id, discountID string) error {
+	return s.discountStore.DeleteDiscount(ctx, id, discountID)
+}
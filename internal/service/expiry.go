@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"subscription/internal/model"
+	"time"
+)
+
+type ExpiryRepository interface {
+	ListExpired(ctx context.Context, before time.Time) ([]model.Subscription, error)
+}
+
+// ExpireSubscriptions переводит в expired подписки, месяц окончания которых уже прошел,
+// и возвращает число переведенных. Истекшие подписки пропадают из списка подписок,
+// но продолжают учитываться в расчетах за прошлые периоды.
+func (s *ServiceStore) ExpireSubscriptions(ctx context.Context) (int, error) {
+
+	subs, err := s.expiryStore.ListExpired(ctx, model.MonthStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	var errs []error
+	for _, sub := range subs {
+		// Статус меняется с первого месяца после окончания подписки
+		effective := model.MonthStart(sub.EndDate.Time).AddDate(0, 1, 0)
+		if err := s.applyTransition(ctx, &sub, model.StatusExpired, "end date passed", effective); err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.ID, err))
+			continue
+		}
+		if err := s.events.Publish(ctx, model.NewEvent(model.EventExpired, sub)); err != nil {
+			log.Printf("failed to publish expiry: id=%s: %v", sub.ID, err)
+		}
+		expired++
+	}
+	return expired, errors.Join(errs...)
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"subscription/internal/model"
+	"time"
+)
+
+// Forecast прогнозирует списания на months месяцев вперед, начиная с текущего.
+// Учитываются периодичность оплаты, запланированные изменения цены, скидки,
+// пробные периоды, приостановки и даты окончания подписок.
+func (s *ServiceStore) Forecast(ctx context.Context, userId string, months int) (model.Forecast, error) {
+
+	from := model.MonthStart(time.Now())
+	to := from.AddDate(0, months-1, 0)
+
+	subs, err := s.listForPeriod(ctx, model.Filter{UserId: userId}, from, to)
+	if err != nil {
+		return model.Forecast{}, err
+	}
+	return forecast(subs, userId, from, to), nil
+}
+
+// forecast раскладывает списания подписок по месяцам периода [from, to]
+func forecast(subs []model.Subscription, userId string, from, to time.Time) model.Forecast {
+	byMonth := make(map[time.Time][]model.Charge)
+	for _, c := range charges(subs, userId, from, to) {
+		byMonth[c.Month.Time] = append(byMonth[c.Month.Time], c)
+	}
+
+	result := model.Forecast{
+		UserId: userId,
+		From:   model.CustomDate{Time: from},
+		To:     model.CustomDate{Time: to},
+		Months: []model.ForecastMonth{},
+	}
+	for m := from; !m.After(to); m = m.AddDate(0, 1, 0) {
+		list := byMonth[m]
+		t := totals(list)
+		result.Total.Gross += t.Gross
+		result.Total.Net += t.Net
+		if list == nil {
+			list = []model.Charge{}
+		}
+		result.Months = append(result.Months, model.ForecastMonth{
+			Month:      model.CustomDate{Time: m},
+			Gross:      t.Gross,
+			Amount:     t.Net,
+			Cumulative: result.Total.Net,
+			Charges:    list,
+		})
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type HouseholdRepository interface {
+	CreateHousehold(ctx context.Context, h model.Household) error
+	GetHousehold(ctx context.Context, id string) (model.Household, error)
+	ListHouseholdSubscriptions(ctx context.Context, householdID string, from, to time.Time) ([]model.Subscription, error)
+}
+
+func (s *ServiceStore) CreateHousehold(ctx context.Context, dto datatransfer.DTOHousehold) (model.Household, error) {
+
+	h := model.NewHousehold(dto)
+	if err := s.householdStore.CreateHousehold(ctx, h); err != nil {
+		return model.Household{}, err
+	}
+	return h, nil
+}
+
+func (s *ServiceStore) GetHousehold(ctx context.Context, id string) (model.Household, error) {
+	return s.householdStore.GetHousehold(ctx, id)
+}
+
+// Balances считает, кто кому должен за совместные подписки домохозяйства за период.
+// Владелец подписки оплачивает её целиком, остальные участники должны ему свою долю
+// в стоимости со скидками, как в расчете суммы.
+func (s *ServiceStore) Balances(ctx context.Context, householdID string, from, to time.Time) (model.Balances, error) {
+
+	if _, err := s.householdStore.GetHousehold(ctx, householdID); err != nil {
+		return model.Balances{}, err
+	}
+
+	subs, err := s.householdStore.ListHouseholdSubscriptions(ctx, householdID, from, to)
+	if err != nil {
+		return model.Balances{}, err
+	}
+
+	owners := make(map[string]string, len(subs))
+	for _, sub := range subs {
+		owners[sub.ID] = sub.UserId
+	}
+	net := make(map[string]int)
+	for _, c := range memberCharges(subs, from, to) {
+		owner := owners[c.SubscriptionID]
+		if c.UserId == owner || c.Amount == 0 {
+			continue
+		}
+		net[c.UserId] -= c.Amount
+		net[owner] += c.Amount
+	}
+
+	return model.Balances{
+		HouseholdID: householdID,
+		Net:         net,
+		Debts:       model.Settle(net),
+	}, nil
+}
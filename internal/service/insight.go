@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"subscription/internal/model"
+	"time"
+)
+
+// Insights ищет признаки лишних расходов среди действующих и будущих подписок пользователя:
+// повторную оплату одного сервиса в пересекающиеся периоды и несколько сервисов одной категории.
+func (s *ServiceStore) Insights(ctx context.Context, userId string) (model.Insights, error) {
+
+	subs, err := s.subscriptionStore.GetAll(ctx, model.Filter{UserId: userId})
+	if err != nil {
+		return model.Insights{}, err
+	}
+	return insights(subs, userId, model.MonthStart(time.Now())), nil
+}
+
+// insights строит находки по подпискам, которые не закончились до месяца now
+func insights(subs []model.Subscription, userId string, now time.Time) model.Insights {
+	result := model.Insights{UserId: userId, Findings: []model.Insight{}}
+
+	cost := make(map[string]int)
+	byService := make(map[string][]model.Subscription)
+	var services []string
+	for _, sub := range subs {
+		if sub.Status == model.StatusCancelled || sub.Status == model.StatusExpired {
+			continue
+		}
+		if sub.EndDate != nil && sub.EndDate.Time.Before(now) {
+			continue
+		}
+		month := now
+		if start := model.MonthStart(sub.StartDate.Time); start.After(month) {
+			month = start
+		}
+		cost[sub.ID] = sub.MonthlyCost(userId, month)
+
+		key := serviceKey(sub)
+		if _, ok := byService[key]; !ok {
+			services = append(services, key)
+		}
+		byService[key] = append(byService[key], sub)
+	}
+	sort.Strings(services)
+
+	// Повторная оплата одного сервиса: группы подписок с пересекающимися периодами
+	for _, key := range services {
+		for _, group := range overlapping(byService[key]) {
+			if len(group) < 2 {
+				continue
+			}
+			finding := newInsight(model.InsightDuplicate, model.NormalizeLabel(group[0].ServiceName), group, cost)
+			finding.Message = fmt.Sprintf("%s is paid %d times in overlapping periods", group[0].ServiceName, len(group))
+			result.Findings = append(result.Findings, finding)
+		}
+	}
+
+	// Несколько сервисов одной категории: от каждого сервиса берется самая дорогая подписка,
+	// чтобы не учитывать повторно экономию от дублей
+	byCategory := make(map[string][]model.Subscription)
+	var categories []string
+	for _, key := range services {
+		list := byService[key]
+		top := list[0]
+		for _, sub := range list[1:] {
+			if cost[sub.ID] > cost[top.ID] {
+				top = sub
+			}
+		}
+		category := model.NormalizeLabel(top.Category)
+		if category == "" {
+			continue
+		}
+		if _, ok := byCategory[category]; !ok {
+			categories = append(categories, category)
+		}
+		byCategory[category] = append(byCategory[category], top)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		group := byCategory[category]
+		if len(group) < 2 {
+			continue
+		}
+		names := make([]string, 0, len(group))
+		for _, sub := range group {
+			names = append(names, sub.ServiceName)
+		}
+		finding := newInsight(model.InsightSameCategory, category, group, cost)
+		finding.Message = fmt.Sprintf("%d services in category %s: %s", len(group), category, strings.Join(names, ", "))
+		result.Findings = append(result.Findings, finding)
+	}
+
+	for _, f := range result.Findings {
+		result.EstimatedSavings += f.EstimatedSavings
+	}
+	return result
+}
+
+// newInsight считает стоимость группы и экономию, если оставить самую дорогую подписку
+func newInsight(kind model.InsightKind, key string, group []model.Subscription, cost map[string]int) model.Insight {
+	finding := model.Insight{Kind: kind, Key: key}
+	top := 0
+	for _, sub := range group {
+		finding.SubscriptionIDs = append(finding.SubscriptionIDs, sub.ID)
+		finding.MonthlyCost += cost[sub.ID]
+		top = max(top, cost[sub.ID])
+	}
+	finding.EstimatedSavings = finding.MonthlyCost - top
+	return finding
+}
+
+// serviceKey идентифицирует сервис: по записи справочника, иначе по нормализованному названию
+func serviceKey(sub model.Subscription) string {
+	if sub.CatalogID != nil {
+		return "catalog:" + *sub.CatalogID
+	}
+	return "name:" + model.NormalizeLabel(sub.ServiceName)
+}
+
+// overlapping разбивает подписки на группы с пересекающимися по месяцам периодами
+func overlapping(subs []model.Subscription) [][]model.Subscription {
+	sorted := append([]model.Subscription(nil), subs...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].StartDate.Before(sorted[j].StartDate.Time) })
+
+	var groups [][]model.Subscription
+	// groupEnd последний месяц текущей группы, open — группа не ограничена датой окончания
+	var groupEnd time.Time
+	open := false
+	for _, sub := range sorted {
+		start := model.MonthStart(sub.StartDate.Time)
+		if len(groups) == 0 || (!open && start.After(groupEnd)) {
+			groups = append(groups, nil)
+			open, groupEnd = false, start
+		}
+		if sub.EndDate == nil {
+			open = true
+		} else if end := model.MonthStart(sub.EndDate.Time); end.After(groupEnd) {
+			groupEnd = end
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], sub)
+	}
+	return groups
+}
@@ -0,0 +1,15 @@
+package service
+
+import (
+	"context"
+	"subscription/internal/model"
+)
+
+type JobRepository interface {
+	ListJobRuns(ctx context.Context) ([]model.JobRun, error)
+}
+
+// Jobs возвращает фоновые задачи с результатами последних запусков на всех репликах
+func (s *ServiceStore) Jobs(ctx context.Context) ([]model.JobRun, error) {
+	return s.jobStore.ListJobRuns(ctx)
+}
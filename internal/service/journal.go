@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"sort"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type JournalRepository interface {
+	GetJournalAccounts(ctx context.Context, userId string) (model.JournalAccounts, bool, error)
+	SaveJournalAccounts(ctx context.Context, a model.JournalAccounts) error
+}
+
+// JournalAccounts возвращает счета пользователя для проводок или счета по умолчанию
+func (s *ServiceStore) JournalAccounts(ctx context.Context, userId string) (model.JournalAccounts, error) {
+
+	accounts, ok, err := s.journalStore.GetJournalAccounts(ctx, userId)
+	if err != nil {
+		return model.JournalAccounts{}, err
+	}
+	if !ok {
+		return model.DefaultJournalAccounts(userId), nil
+	}
+	if accounts.Categories == nil {
+		accounts.Categories = map[string]string{}
+	}
+	return accounts, nil
+}
+
+func (s *ServiceStore) SetJournalAccounts(ctx context.Context, userId string, dto datatransfer.DTOJournalAccounts) (model.JournalAccounts, error) {
+
+	accounts := model.NewJournalAccounts(userId, dto)
+	if err := s.journalStore.SaveJournalAccounts(ctx, accounts); err != nil {
+		return model.JournalAccounts{}, err
+	}
+	return accounts, nil
+}
+
+// Journal готовит проводки пользователя за период: ожидаемые списания (в совместных подписках —
+// доля пользователя) или фактические платежи, которые пользователь оплатил как владелец подписки.
+// Расходный счет выбирается по категории подписки.
+func (s *ServiceStore) Journal(ctx context.Context, userId string, from, to time.Time, format model.JournalFormat, source model.JournalSource) (model.Journal, error) {
+
+	accounts, err := s.JournalAccounts(ctx, userId)
+	if err != nil {
+		return model.Journal{}, err
+	}
+	subs, err := s.listForPeriod(ctx, model.Filter{UserId: userId}, from, to)
+	if err != nil {
+		return model.Journal{}, err
+	}
+
+	journal := model.Journal{
+		UserId: userId,
+		Format: format,
+		Source: source,
+		From:   model.MonthStart(from),
+		To:     model.MonthStart(to).AddDate(0, 1, -1),
+	}
+
+	if source == model.JournalActual {
+		byID := make(map[string]model.Subscription, len(subs))
+		ids := make([]string, 0, len(subs))
+		for _, sub := range subs {
+			byID[sub.ID] = sub
+			ids = append(ids, sub.ID)
+		}
+		payments, err := s.paymentStore.ListPaymentsForPeriod(ctx, ids, journal.From, journal.To)
+		if err != nil {
+			return model.Journal{}, err
+		}
+		for _, p := range payments {
+			// Платеж записывается целиком на владельца, который его оплатил:
+			// участники совместной подписки этих денег со своих счетов не платили
+			sub := byID[p.SubscriptionID]
+			if sub.UserId != userId {
+				continue
+			}
+			journal.Entries = append(journal.Entries, model.JournalEntry{
+				Date:           p.PaidAt,
+				Payee:          sub.ServiceName,
+				SubscriptionID: p.SubscriptionID,
+				Reference:      p.Reference,
+				Account:        accounts.ExpenseAccount(sub.Category),
+				Funding:        accounts.Funding,
+				Amount:         p.Amount,
+				Currency:       p.Currency,
+			})
+		}
+	} else {
+		for _, c := range charges(subs, userId, from, to) {
+			journal.Entries = append(journal.Entries, model.JournalEntry{
+				Date:           c.Month.Time,
+				Payee:          c.ServiceName,
+				SubscriptionID: c.SubscriptionID,
+				Account:        accounts.ExpenseAccount(c.Category),
+				Funding:        accounts.Funding,
+				Amount:         c.Amount,
+				Currency:       model.BaseCurrency,
+			})
+		}
+	}
+
+	sort.SliceStable(journal.Entries, func(i, j int) bool {
+		a, b := journal.Entries[i], journal.Entries[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.Payee < b.Payee
+	})
+	return journal, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"sort"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, p model.Payment) error
+	GetPayment(ctx context.Context, subscriptionID, id string) (model.Payment, error)
+	ListPayments(ctx context.Context, subscriptionID string) ([]model.Payment, error)
+	ListPaymentsForPeriod(ctx context.Context, ids []string, from, to time.Time) ([]model.Payment, error)
+	UpdatePayment(ctx context.Context, p model.Payment) error
+	DeletePayment(ctx context.Context, subscriptionID, id string) error
+}
+
+func (s *ServiceStore) AddPayment(ctx context.Context, id string, dto datatransfer.DTOPayment) (model.Payment, error) {
+
+	if _, err := s.subscriptionStore.GetByID(ctx, id); err != nil {
+		return model.Payment{}, err
+	}
+
+	payment, err := model.NewPayment(id, dto)
+	if err != nil {
+		return model.Payment{}, err
+	}
+	if err := s.paymentStore.CreatePayment(ctx, payment); err != nil {
+		return model.Payment{}, err
+	}
+	return payment, nil
+}
+
+func (s *ServiceStore) GetPayment(ctx context.Context, id, paymentID string) (model.Payment, error) {
+	return s.paymentStore.GetPayment(ctx, id, paymentID)
+}
+
+func (s *ServiceStore) ListPayments(ctx context.Context, id string) ([]model.Payment, error) {
+
+	if _, err := s.subscriptionStore.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	payments, err := s.paymentStore.ListPayments(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if payments == nil {
+		return []model.Payment{}, nil
+	}
+	return payments, nil
+}
+
+func (s *ServiceStore) UpdatePayment(ctx context.Context, id, paymentID string, dto datatransfer.DTOPayment) (model.Payment, error) {
+
+	if _, err := s.paymentStore.GetPayment(ctx, id, paymentID); err != nil {
+		return model.Payment{}, err
+	}
+
+	updated, err := model.NewPayment(id, dto)
+	if err != nil {
+		return model.Payment{}, err
+	}
+	updated.ID = paymentID
+	if err := s.paymentStore.UpdatePayment(ctx, updated); err != nil {
+		return model.Payment{}, err
+	}
+	return updated, nil
+}
+
+func (s *ServiceStore) DeletePayment(ctx context.Context, id, paymentID string) error {
+	return s.paymentStore.DeletePayment(ctx, id, paymentID)
+}
+
+// Reconcile сверяет ожидаемые списания подписок за период с фактическими платежами.
+// Платеж относится к месяцу, в котором он прошел; списания считаются полной стоимостью,
+// которую оплачивает владелец подписки.
+func (s *ServiceStore) Reconcile(ctx context.Context, filter model.Filter, from, to time.Time) (model.Reconciliation, error) {
+
+	subs, err := s.listForPeriod(ctx, filter, from, to)
+	if err != nil {
+		return model.Reconciliation{}, err
+	}
+	ids := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		ids = append(ids, sub.ID)
+	}
+	payments, err := s.paymentStore.ListPaymentsForPeriod(ctx, ids, from, model.MonthStart(to).AddDate(0, 1, -1))
+	if err != nil {
+		return model.Reconciliation{}, err
+	}
+	return reconcile(subs, charges(subs, "", from, to), payments, from, to), nil
+}
+
+// reconcile сопоставляет списания и платежи по подписке и месяцу
+func reconcile(subs []model.Subscription, list []model.Charge, payments []model.Payment, from, to time.Time) model.Reconciliation {
+	type key struct {
+		subscription string
+		month        time.Time
+	}
+	names := make(map[string]string, len(subs))
+	for _, sub := range subs {
+		names[sub.ID] = sub.ServiceName
+	}
+
+	items := make(map[key]*model.ReconciliationItem)
+	item := func(k key) *model.ReconciliationItem {
+		it, ok := items[k]
+		if !ok {
+			it = &model.ReconciliationItem{SubscriptionID: k.subscription, ServiceName: names[k.subscription], Payments: []model.Payment{}}
+			items[k] = it
+		}
+		return it
+	}
+	for _, c := range list {
+		item(key{c.SubscriptionID, c.Month.Time}).Expected += c.Amount
+	}
+	foreign := make(map[key]bool)
+	for _, p := range payments {
+		k := key{p.SubscriptionID, model.MonthStart(p.PaidAt)}
+		it := item(k)
+		it.Payments = append(it.Payments, p)
+		if p.Currency != model.BaseCurrency {
+			foreign[k] = true
+			continue
+		}
+		it.Paid += p.Amount
+	}
+
+	byMonth := make(map[time.Time][]model.ReconciliationItem)
+	for k, it := range items {
+		it.Difference = it.Paid - it.Expected
+		switch {
+		case foreign[k]:
+			it.Status = model.ReconcileCurrency
+		case it.Expected == 0:
+			it.Status = model.ReconcileUnexpected
+		case len(it.Payments) == 0:
+			it.Status = model.ReconcileMissing
+		case it.Difference < 0:
+			it.Status = model.ReconcileUnderpaid
+		case it.Difference > 0:
+			it.Status = model.ReconcileOverpaid
+		default:
+			it.Status = model.ReconcilePaid
+		}
+		byMonth[k.month] = append(byMonth[k.month], *it)
+	}
+
+	result := model.Reconciliation{
+		From:   model.CustomDate{Time: from},
+		To:     model.CustomDate{Time: to},
+		Months: []model.ReconciliationMonth{},
+	}
+	for m := model.MonthStart(from); !m.After(to); m = m.AddDate(0, 1, 0) {
+		month := model.ReconciliationMonth{Month: model.CustomDate{Time: m}, Items: byMonth[m]}
+		if month.Items == nil {
+			month.Items = []model.ReconciliationItem{}
+		}
+		sort.Slice(month.Items, func(i, j int) bool {
+			a, b := month.Items[i], month.Items[j]
+			if a.ServiceName != b.ServiceName {
+				return a.ServiceName < b.ServiceName
+			}
+			return a.SubscriptionID < b.SubscriptionID
+		})
+		for _, it := range month.Items {
+			month.Expected += it.Expected
+			month.Paid += it.Paid
+		}
+		result.Expected += month.Expected
+		result.Paid += month.Paid
+		result.Months = append(result.Months, month)
+	}
+	result.Difference = result.Paid - result.Expected
+	return result
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"slices"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PlanRepository interface {
+	CreatePlan(ctx context.Context, p model.Plan) error
+	GetPlan(ctx context.Context, id string) (model.Plan, error)
+	ListPlans(ctx context.Context, catalogID string) ([]model.Plan, error)
+	ChangePlan(ctx context.Context, sub model.Subscription, change model.PlanChange) error
+	ListPlanChanges(ctx context.Context, subscriptionID string) ([]model.PlanChange, error)
+}
+
+func (s *ServiceStore) CreatePlan(ctx context.Context, catalogID string, dto datatransfer.DTOPlan) (model.Plan, error) {
+
+	if _, err := s.catalogStore.GetCatalogEntry(ctx, catalogID); err != nil {
+		return model.Plan{}, err
+	}
+	p := model.NewPlan(catalogID, dto)
+	if err := s.planStore.CreatePlan(ctx, p); err != nil {
+		return model.Plan{}, err
+	}
+	return p, nil
+}
+
+func (s *ServiceStore) ListPlans(ctx context.Context, catalogID string) ([]model.Plan, error) {
+
+	if _, err := s.catalogStore.GetCatalogEntry(ctx, catalogID); err != nil {
+		return nil, err
+	}
+	return s.planStore.ListPlans(ctx, catalogID)
+}
+
+// ChangePlan переводит подписку на другой тариф с указанного месяца.
+// До этого месяца расчеты используют прежнюю цену, начиная с него — цену нового тарифа.
+func (s *ServiceStore) ChangePlan(ctx context.Context, id string, dto datatransfer.DTOChangePlan) (model.Subscription, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	plan, err := s.planStore.GetPlan(ctx, dto.PlanID)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	if sub.CatalogID != nil && *sub.CatalogID != plan.CatalogID {
+		return model.Subscription{}, model.ErrPlanMismatch
+	}
+
+	effective, err := time.Parse("01-2006", dto.EffectiveDate)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+	if effective.Before(model.MonthStart(sub.StartDate.Time)) {
+		return model.Subscription{}, model.ErrEffectiveBeforeStart
+	}
+
+	oldPrice := sub.PriceAt(effective)
+	change := model.PlanChange{
+		ID:             uuid.New().String(),
+		SubscriptionID: sub.ID,
+		FromPlanID:     sub.PlanID,
+		ToPlanID:       &plan.ID,
+		OldPrice:       oldPrice,
+		NewPrice:       plan.Price,
+		Direction:      model.PlanDirection(oldPrice, plan.Price),
+		EffectiveDate:  model.CustomDate{Time: effective},
+		ChangedAt:      time.Now().UTC(),
+	}
+
+	before := sub
+	before.Prices = slices.Clone(sub.Prices)
+	sub.SetPrice(effective, plan.Price)
+	sub.PlanID = &plan.ID
+	sub.CatalogID = &plan.CatalogID
+
+	if err := s.planStore.ChangePlan(ctx, sub, change); err != nil {
+		return model.Subscription{}, err
+	}
+	s.checkBudgets(ctx, &before, sub)
+
+	return sub, nil
+}
+
+func (s *ServiceStore) ListPlanChanges(ctx context.Context, id string) ([]model.PlanChange, error) {
+
+	if _, err := s.subscriptionStore.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.planStore.ListPlanChanges(ctx, id)
+}
+
+// applyPlan берет название сервиса из справочника по тарифу подписки,
+// а цену — из тарифа, если она не указана явно
+func (s *ServiceStore) applyPlan(ctx context.Context, sub *model.Subscription) error {
+
+	if sub.PlanID == nil {
+		return nil
+	}
+	plan, err := s.planStore.GetPlan(ctx, *sub.PlanID)
+	if err != nil {
+		return err
+	}
+	entry, err := s.catalogStore.GetCatalogEntry(ctx, plan.CatalogID)
+	if err != nil {
+		return err
+	}
+	sub.ServiceName = entry.Name
+	if sub.Price == 0 {
+		sub.Price = plan.Price
+	}
+	return nil
+}
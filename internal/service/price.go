@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"slices"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type PriceRepository interface {
+	SavePrices(ctx context.Context, sub model.Subscription) error
+}
+
+// AddPrice записывает новую цену подписки, действующую с указанного месяца.
+// Списания до этого месяца продолжают считаться по прежней цене.
+func (s *ServiceStore) AddPrice(ctx context.Context, id string, dto datatransfer.DTOPrice) ([]model.PricePoint, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	effective, err := time.Parse("01-2006", dto.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	if effective.Before(model.MonthStart(sub.StartDate.Time)) {
+		return nil, model.ErrEffectiveBeforeStart
+	}
+
+	before := sub
+	before.Prices = slices.Clone(sub.Prices)
+	sub.SetPrice(effective, dto.Price)
+	if err := s.priceStore.SavePrices(ctx, sub); err != nil {
+		return nil, err
+	}
+	s.checkBudgets(ctx, &before, sub)
+
+	return sub.PriceHistory(), nil
+}
+
+func (s *ServiceStore) ListPrices(ctx context.Context, id string) ([]model.PricePoint, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return sub.PriceHistory(), nil
+}
+
+// priceChangeMonth возвращает месяц, с которого действует цена, измененная без явной даты:
+// текущий месяц или месяц начала подписки, если она еще не началась
+func priceChangeMonth(sub model.Subscription) time.Time {
+	month := model.MonthStart(time.Now())
+	if start := model.MonthStart(sub.StartDate.Time); start.After(month) {
+		return start
+	}
+	return month
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type ReminderRepository interface {
+	CreateReminder(ctx context.Context, r model.ReminderSetting) error
+	DeleteReminder(ctx context.Context, subscriptionID, id string) error
+	ListRemindable(ctx context.Context) ([]model.Subscription, error)
+	ClaimReminder(ctx context.Context, r model.Reminder) (bool, error)
+	ReleaseReminder(ctx context.Context, r model.Reminder) error
+}
+
+// Notifier доставляет уведомление по одному каналу
+type Notifier interface {
+	Notify(ctx context.Context, msg model.Notification) error
+}
+
+// RegisterNotifier подключает канал доставки уведомлений
+func (s *ServiceStore) RegisterNotifier(channel model.Channel, n Notifier) {
+	s.notifiers[channel] = n
+}
+
+// notify доставляет уведомление через канал, указанный в нем
+func (s *ServiceStore) notify(ctx context.Context, msg model.Notification) error {
+	notifier, ok := s.notifiers[msg.Channel]
+	if !ok {
+		return fmt.Errorf("channel %s is not configured", msg.Channel)
+	}
+	return notifier.Notify(ctx, msg)
+}
+
+func (s *ServiceStore) AddReminder(ctx context.Context, id string, dto datatransfer.DTOReminder) (model.ReminderSetting, error) {
+
+	if _, err := s.subscriptionStore.GetByID(ctx, id); err != nil {
+		return model.ReminderSetting{}, err
+	}
+
+	reminder := model.NewReminderSetting(id, dto)
+	if err := s.reminderStore.CreateReminder(ctx, reminder); err != nil {
+		return model.ReminderSetting{}, err
+	}
+	return reminder, nil
+}
+
+func (s *ServiceStore) ListReminders(ctx context.Context, id string) ([]model.ReminderSetting, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.Reminders == nil {
+		return []model.ReminderSetting{}, nil
+	}
+	return sub.Reminders, nil
+}
+
+func (s *ServiceStore) DeleteReminder(ctx context.Context, id, reminderID string) error {
+	return s.reminderStore.DeleteReminder(ctx, id, reminderID)
+}
+
+// SendReminders отправляет напоминания, срок которых наступил, и возвращает число отправленных.
+// Перед отправкой напоминание отмечается в базе, поэтому повторно оно не уйдет;
+// если доставка не удалась, отметка снимается и отправка повторится при следующем запуске.
+func (s *ServiceStore) SendReminders(ctx context.Context) (int, error) {
+
+	subs, err := s.reminderStore.ListRemindable(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	var errs []error
+	for _, sub := range subs {
+		for _, r := range sub.DueReminders(time.Now()) {
+			ok, err := s.sendReminder(ctx, r)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("reminder %s %s %s: %w", r.SettingID, r.Kind, r.DueDate.Format("2006-01-02"), err))
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+	}
+	return sent, errors.Join(errs...)
+}
+
+// sendReminder отправляет напоминание, если оно еще не отправлялось
+func (s *ServiceStore) sendReminder(ctx context.Context, r model.Reminder) (bool, error) {
+
+	if _, ok := s.notifiers[r.Channel]; !ok {
+		return false, fmt.Errorf("channel %s is not configured", r.Channel)
+	}
+
+	claimed, err := s.reminderStore.ClaimReminder(ctx, r)
+	if err != nil || !claimed {
+		return false, err
+	}
+	if err := s.notify(ctx, r.Notification()); err != nil {
+		if releaseErr := s.reminderStore.ReleaseReminder(ctx, r); releaseErr != nil {
+			return false, errors.Join(err, releaseErr)
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"sort"
 	datatransfer "subscription/internal/api/dto"
 	"subscription/internal/model"
 	"time"
@@ -10,19 +11,84 @@ import (
 type SubscriptionRepository interface {
 	Create(ctx context.Context, sub model.Subscription) error
 	GetByID(ctx context.Context, id string) (model.Subscription, error)
-	GetAll(ctx context.Context) ([]model.Subscription, error)
+	GetAll(ctx context.Context, filter model.Filter) ([]model.Subscription, error)
 	Update(ctx context.Context, id string, sub model.Subscription) error
 	Delete(ctx context.Context, id string) error
-	SumForPeriod(ctx context.Context, userId, serviceName string, from, to time.Time) (int, error)
+	ListForPeriod(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.Subscription, error)
+}
+
+// Repository объединяет все хранилища, с которыми работает сервис
+type Repository interface {
+	SubscriptionRepository
+	HouseholdRepository
+	BudgetRepository
+	CatalogRepository
+	PlanRepository
+	PriceRepository
+	StatusRepository
+	TrialRepository
+	DiscountRepository
+	JobRepository
+	ReminderRepository
+	DigestRepository
+	ExpiryRepository
+	AnalyticsRepository
+	UsageRepository
+	PaymentRepository
+	ProposalRepository
+	JournalRepository
+}
+
+// EventPublisher доставляет доменные события
+type EventPublisher interface {
+	Publish(ctx context.Context, e model.Event) error
 }
 
 type ServiceStore struct {
 	subscriptionStore SubscriptionRepository
+	householdStore    HouseholdRepository
+	budgetStore       BudgetRepository
+	catalogStore      CatalogRepository
+	planStore         PlanRepository
+	priceStore        PriceRepository
+	statusStore       StatusRepository
+	trialStore        TrialRepository
+	discountStore     DiscountRepository
+	jobStore          JobRepository
+	reminderStore     ReminderRepository
+	digestStore       DigestRepository
+	expiryStore       ExpiryRepository
+	analyticsStore    AnalyticsRepository
+	usageStore        UsageRepository
+	paymentStore      PaymentRepository
+	proposalStore     ProposalRepository
+	journalStore      JournalRepository
+	events            EventPublisher
+	notifiers         map[model.Channel]Notifier
 }
 
-func NewService(subStore SubscriptionRepository) *ServiceStore {
+func NewService(repo Repository, events EventPublisher) *ServiceStore {
 	return &ServiceStore{
-		subscriptionStore: subStore,
+		subscriptionStore: repo,
+		householdStore:    repo,
+		budgetStore:       repo,
+		catalogStore:      repo,
+		planStore:         repo,
+		priceStore:        repo,
+		statusStore:       repo,
+		trialStore:        repo,
+		discountStore:     repo,
+		jobStore:          repo,
+		reminderStore:     repo,
+		digestStore:       repo,
+		expiryStore:       repo,
+		analyticsStore:    repo,
+		usageStore:        repo,
+		paymentStore:      repo,
+		proposalStore:     repo,
+		journalStore:      repo,
+		events:            events,
+		notifiers:         make(map[model.Channel]Notifier),
 	}
 }
 
@@ -32,9 +98,16 @@ func (s *ServiceStore) Create(ctx context.Context, dto datatransfer.DTOSubs) (mo
 	if err != nil {
 		return model.Subscription{}, err
 	}
+	if err := s.applyPlan(ctx, &sub); err != nil {
+		return model.Subscription{}, err
+	}
+	if err := s.applyCatalog(ctx, &sub); err != nil {
+		return model.Subscription{}, err
+	}
 	if err := s.subscriptionStore.Create(ctx, sub); err != nil {
 		return model.Subscription{}, err
 	}
+	s.checkBudgets(ctx, nil, sub)
 
 	return sub, nil
 
@@ -49,9 +122,13 @@ func (s *ServiceStore) GetInfo(ctx context.Context, idSub string) (model.Subscri
 	return sub, nil
 }
 
-func (s *ServiceStore) GetAll(ctx context.Context) ([]model.Subscription, error) {
+func (s *ServiceStore) GetAll(ctx context.Context, filter model.Filter) ([]model.Subscription, error) {
 
-	allSub, err := s.subscriptionStore.GetAll(ctx)
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return []model.Subscription{}, err
+	}
+	allSub, err := s.subscriptionStore.GetAll(ctx, filter)
 	if err != nil {
 		return []model.Subscription{}, err
 	}
@@ -69,20 +146,113 @@ func (s *ServiceStore) Update(ctx context.Context, id string, dto datatransfer.D
 		return model.Subscription{}, err
 	}
 	updatedSub := model.Subscription{
-		ServiceName: dto.ServiceName,
-		Price:       dto.Price,
-		UserId:      oldSub.UserId,
-		StartDate:   oldSub.StartDate,
+		ID:            oldSub.ID,
+		ServiceName:   dto.ServiceName,
+		Price:         oldSub.Price,
+		UserId:        oldSub.UserId,
+		StartDate:     oldSub.StartDate,
+		EndDate:       oldSub.EndDate,
+		HouseholdID:   oldSub.HouseholdID,
+		SplitType:     oldSub.SplitType,
+		Members:       oldSub.Members,
+		Category:      oldSub.Category,
+		Tags:          oldSub.Tags,
+		PlanID:        oldSub.PlanID,
+		Status:        oldSub.Status,
+		TrialEnd:      oldSub.TrialEnd,
+		Prices:        oldSub.Prices,
+		StatusHistory: oldSub.StatusHistory,
+		BillingCycle:  oldSub.BillingCycle,
+	}
+	// Не указанные в запросе поля остаются прежними. Тип разделения и участники
+	// заменяются вместе, чтобы доли всегда проверялись по своему типу
+	if dto.HouseholdID != "" {
+		updatedSub.HouseholdID = &dto.HouseholdID
+	}
+	if dto.SplitType != "" || dto.Members != nil {
+		updatedSub.SplitType = model.NewSplitType(dto.SplitType)
+		updatedSub.Members = model.NewMembers(dto.Members)
+	}
+	if dto.Category != "" {
+		updatedSub.Category = model.NormalizeLabel(dto.Category)
+	}
+	if dto.Tags != nil {
+		updatedSub.Tags = model.NewTags(dto.Tags)
+	}
+	if dto.BillingCycle != "" {
+		updatedSub.BillingCycle = model.NewBillingCycle(dto.BillingCycle)
+	}
+	// Новая цена не переписывает прошлые списания, а действует с текущего месяца
+	if dto.Price != oldSub.Price {
+		updatedSub.SetPrice(priceChangeMonth(oldSub), dto.Price)
+	}
+	if err := s.applyCatalog(ctx, &updatedSub); err != nil {
+		return model.Subscription{}, err
 	}
 
 	if err := s.subscriptionStore.Update(ctx, id, updatedSub); err != nil {
 		return model.Subscription{}, err
 	}
+	s.checkBudgets(ctx, &oldSub, updatedSub)
 
 	return updatedSub, nil
 }
 
-func (s *ServiceStore) Sum(ctx context.Context, userId, serviceName string, from, to time.Time) (int, error) {
+// Sum считает стоимость подписок за период как сумму ежемесячных списаний до и после скидок.
+// Если в фильтре указан пользователь, для совместных подписок учитывается только его доля.
+func (s *ServiceStore) Sum(ctx context.Context, filter model.Filter, from, to time.Time) (model.Totals, error) {
+
+	subs, err := s.listForPeriod(ctx, filter, from, to)
+	if err != nil {
+		return model.Totals{}, err
+	}
+	return totals(charges(subs, filter.UserId, from, to)), nil
+}
+
+// SumByCategory считает стоимость подписок за период отдельно по каждой категории
+func (s *ServiceStore) SumByCategory(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.CategoryTotal, error) {
+
+	subs, err := s.listForPeriod(ctx, filter, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]int)
+	for _, c := range charges(subs, filter.UserId, from, to) {
+		totals[c.Category] += c.Amount
+	}
+
+	result := make([]model.CategoryTotal, 0, len(totals))
+	for category, sum := range totals {
+		result = append(result, model.CategoryTotal{Category: category, Total: sum})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Category < result[j].Category })
+	return result, nil
+}
 
-	return s.subscriptionStore.SumForPeriod(ctx, userId, serviceName, from, to)
+// resolveFilter приводит название сервиса в фильтре к каноническому из справочника
+func (s *ServiceStore) resolveFilter(ctx context.Context, filter model.Filter) (model.Filter, error) {
+
+	if filter.ServiceName == "" {
+		return filter, nil
+	}
+	entry, ok, err := s.ResolveService(ctx, filter.ServiceName)
+	if err != nil {
+		return model.Filter{}, err
+	}
+	if ok {
+		filter.ServiceName = entry.Name
+		filter.CatalogID = entry.ID
+	}
+	return filter, nil
+}
+
+// listForPeriod возвращает подписки, по которым есть списания в периоде
+func (s *ServiceStore) listForPeriod(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.Subscription, error) {
+
+	filter, err := s.resolveFilter(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.subscriptionStore.ListForPeriod(ctx, filter, from, to)
 }
@@ -0,0 +1,1431 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"subscription/internal/report"
+	"subscription/internal/service"
+	"testing"
+	"time"
+)
+
+type fakeRepo struct {
+	service.Repository
+	subs    []model.Subscription
+	budgets []model.Budget
+	plans   []model.Plan
+	sent    map[string]bool
+
+	payments  []model.Payment
+	proposals []model.Proposal
+	accounts  map[string]model.JournalAccounts
+
+	failConfirm bool
+}
+
+func (f *fakeRepo) Create(ctx context.Context, sub model.Subscription) error {
+	f.subs = append(f.subs, sub)
+	return nil
+}
+func (f *fakeRepo) Update(ctx context.Context, id string, sub model.Subscription) error {
+	for i := range f.subs {
+		if f.subs[i].ID == id {
+			f.subs[i] = sub
+			return nil
+		}
+	}
+	return sql.ErrNoRows
+}
+func (f *fakeRepo) ListForPeriod(ctx context.Context, filter model.Filter, from, to time.Time) ([]model.Subscription, error) {
+	return f.subs, nil
+}
+func (f *fakeRepo) GetAll(ctx context.Context, filter model.Filter) ([]model.Subscription, error) {
+	return f.subs, nil
+}
+func (f *fakeRepo) RecordUsage(ctx context.Context, u model.Usage) (model.Usage, error) {
+	for i := range f.subs {
+		if f.subs[i].ID != u.SubscriptionID {
+			continue
+		}
+		if prev := f.subs[i].Usage; prev != nil {
+			u.Count += prev.Count
+			if prev.LastUsedAt.After(u.LastUsedAt) {
+				u.LastUsedAt = prev.LastUsedAt
+			}
+		}
+		f.subs[i].Usage = &u
+	}
+	return u, nil
+}
+func (f *fakeRepo) CreatePayment(ctx context.Context, p model.Payment) error {
+	f.payments = append(f.payments, p)
+	return nil
+}
+func (f *fakeRepo) ListPaymentsForPeriod(ctx context.Context, ids []string, from, to time.Time) ([]model.Payment, error) {
+	var result []model.Payment
+	for _, p := range f.payments {
+		if !p.PaidAt.Before(from) && !p.PaidAt.After(to) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+func (f *fakeRepo) GetJournalAccounts(ctx context.Context, userId string) (model.JournalAccounts, bool, error) {
+	a, ok := f.accounts[userId]
+	return a, ok, nil
+}
+func (f *fakeRepo) SaveJournalAccounts(ctx context.Context, a model.JournalAccounts) error {
+	if f.accounts == nil {
+		f.accounts = make(map[string]model.JournalAccounts)
+	}
+	f.accounts[a.UserId] = a
+	return nil
+}
+func (f *fakeRepo) ListServiceNames(ctx context.Context) ([]string, error) {
+	return []string{"Яндекс Плюс"}, nil
+}
+func (f *fakeRepo) ReplaceProposals(ctx context.Context, userId string, proposals []model.Proposal) error {
+	f.proposals = proposals
+	return nil
+}
+func (f *fakeRepo) ListProposals(ctx context.Context, userId string) ([]model.Proposal, error) {
+	var result []model.Proposal
+	for _, p := range f.proposals {
+		if p.UserId == userId && p.Status == model.ProposalPending {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+func (f *fakeRepo) ConfirmProposals(ctx context.Context, proposals []model.Proposal, subscriptions []model.Subscription) error {
+	if f.failConfirm {
+		return errors.New("insert failed")
+	}
+	for i, p := range proposals {
+		for j := range f.proposals {
+			if f.proposals[j].ID == p.ID {
+				f.proposals[j].Status = model.ProposalConfirmed
+				f.proposals[j].SubscriptionID = &subscriptions[i].ID
+			}
+		}
+	}
+	f.subs = append(f.subs, subscriptions...)
+	return nil
+}
+func (f *fakeRepo) GetByID(ctx context.Context, id string) (model.Subscription, error) {
+	for _, s := range f.subs {
+		if s.ID == id {
+			return s, nil
+		}
+	}
+	return model.Subscription{}, sql.ErrNoRows
+}
+func (f *fakeRepo) GetPlan(ctx context.Context, id string) (model.Plan, error) {
+	for _, p := range f.plans {
+		if p.ID == id {
+			return p, nil
+		}
+	}
+	return model.Plan{}, sql.ErrNoRows
+}
+func (f *fakeRepo) ChangePlan(ctx context.Context, sub model.Subscription, change model.PlanChange) error {
+	return nil
+}
+func (f *fakeRepo) SavePrices(ctx context.Context, sub model.Subscription) error {
+	for i := range f.subs {
+		if f.subs[i].ID == sub.ID {
+			f.subs[i] = sub
+		}
+	}
+	return nil
+}
+func (f *fakeRepo) ChangeStatus(ctx context.Context, sub model.Subscription, change model.StatusChange) error {
+	return f.SavePrices(ctx, sub)
+}
+func (f *fakeRepo) CreateDiscount(ctx context.Context, d model.Discount) error {
+	for i := range f.subs {
+		if f.subs[i].ID == d.SubscriptionID {
+			f.subs[i].Discounts = append(f.subs[i].Discounts, d)
+		}
+	}
+	return nil
+}
+func (f *fakeRepo) ListExpired(ctx context.Context, before time.Time) ([]model.Subscription, error) {
+	var expired []model.Subscription
+	for _, s := range f.subs {
+		if s.EndDate != nil && s.EndDate.Before(before) && s.Status != model.StatusExpired {
+			expired = append(expired, s)
+		}
+	}
+	return expired, nil
+}
+func (f *fakeRepo) ListRemindable(ctx context.Context) ([]model.Subscription, error) {
+	return f.subs, nil
+}
+func (f *fakeRepo) ClaimReminder(ctx context.Context, r model.Reminder) (bool, error) {
+	key := r.SettingID + string(r.Kind) + r.DueDate.String()
+	if f.sent == nil {
+		f.sent = map[string]bool{}
+	}
+	if f.sent[key] {
+		return false, nil
+	}
+	f.sent[key] = true
+	return true, nil
+}
+func (f *fakeRepo) ReleaseReminder(ctx context.Context, r model.Reminder) error {
+	delete(f.sent, r.SettingID+string(r.Kind)+r.DueDate.String())
+	return nil
+}
+func (f *fakeRepo) ListCatalog(ctx context.Context) ([]model.CatalogEntry, error) {
+	return []model.CatalogEntry{{ID: "1", Name: "Netflix", Aliases: []string{"нетфликс"}, Category: "entertainment"}}, nil
+}
+func (f *fakeRepo) ListUnlinkedServiceNames(ctx context.Context) ([]string, error) {
+	var names []string
+	for _, sub := range f.subs {
+		if sub.CatalogID == nil && !slices.Contains(names, sub.ServiceName) {
+			names = append(names, sub.ServiceName)
+		}
+	}
+	return names, nil
+}
+func (f *fakeRepo) LinkCatalog(ctx context.Context, c model.CatalogEntry, names []string) (int, error) {
+	linked := 0
+	for i := range f.subs {
+		if f.subs[i].CatalogID == nil && slices.Contains(names, f.subs[i].ServiceName) {
+			f.subs[i].CatalogID = &c.ID
+			f.subs[i].ServiceName = c.Name
+			if f.subs[i].Category == "" {
+				f.subs[i].Category = c.Category
+			}
+			linked++
+		}
+	}
+	return linked, nil
+}
+func (f *fakeRepo) GetHousehold(ctx context.Context, id string) (model.Household, error) {
+	return model.Household{ID: id}, nil
+}
+func (f *fakeRepo) ListHouseholdSubscriptions(ctx context.Context, householdID string, from, to time.Time) ([]model.Subscription, error) {
+	var result []model.Subscription
+	for _, sub := range f.subs {
+		if sub.HouseholdID != nil && *sub.HouseholdID == householdID {
+			result = append(result, sub)
+		}
+	}
+	return result, nil
+}
+func (f *fakeRepo) ListBudgets(ctx context.Context, userId string) ([]model.Budget, error) {
+	return f.budgets, nil
+}
+
+type fakePublisher struct {
+	events []model.Event
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, e model.Event) error {
+	f.events = append(f.events, e)
+	return nil
+}
+
+const testUser = "a37a0327-99af-4e62-8b33-55dc3863cdc6"
+
+func TestSum_Unit(t *testing.T) {
+
+	start, _ := time.Parse("01-2006", "01-2025")
+	end, _ := time.Parse("01-2006", "03-2025")
+	repo := &fakeRepo{subs: []model.Subscription{{
+		ID:          "1",
+		ServiceName: "Netflix",
+		Price:       400,
+		UserId:      testUser,
+		StartDate:   model.CustomDate{Time: start},
+		EndDate:     &model.CustomDate{Time: end},
+	}}}
+	s := service.NewService(repo, &fakePublisher{})
+
+	from, _ := time.Parse("01-2006", "02-2025")
+	to, _ := time.Parse("01-2006", "12-2025")
+	sum, err := s.Sum(context.Background(), model.Filter{}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Net != 800 {
+		t.Fatalf("expected sum %d, got %d", 800, sum.Net)
+	}
+
+}
+
+func TestCreateBudgetExceeded_Unit(t *testing.T) {
+
+	repo := &fakeRepo{budgets: []model.Budget{{ID: "1", UserId: testUser, MonthlyLimit: 500}}}
+	events := &fakePublisher{}
+	s := service.NewService(repo, events)
+
+	_, err := s.Create(context.Background(), datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       600,
+		UserId:      testUser,
+		StartDate:   time.Now().Format("01-2006"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events.events) != 1 || events.events[0].Type != model.EventBudgetExceeded {
+		t.Fatalf("expected one %s event, got %v", model.EventBudgetExceeded, events.events)
+	}
+
+}
+
+func TestBudgetExceededOnce_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{budgets: []model.Budget{{ID: "1", UserId: testUser, MonthlyLimit: 500}}}
+	events := &fakePublisher{}
+	s := service.NewService(repo, events)
+
+	month := time.Now().Format("01-2006")
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Netflix", Price: 300, UserId: testUser, StartDate: month},
+		{ServiceName: "Spotify", Price: 300, UserId: testUser, StartDate: month},
+		// Бюджет уже превышен, повторного уведомления быть не должно
+		{ServiceName: "iCloud", Price: 100, UserId: testUser, StartDate: month},
+	} {
+		if _, err := s.Create(ctx, dto); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(events.events) != 1 || events.events[0].Type != model.EventBudgetExceeded {
+		t.Fatalf("expected a single %s event, got %v", model.EventBudgetExceeded, events.events)
+	}
+}
+
+func TestCreateResolvesCatalog_Unit(t *testing.T) {
+
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	for _, name := range []string{"netflix ", "NETFLIX Premium", "Netflx", "Нетфликс"} {
+		sub, err := s.Create(context.Background(), datatransfer.DTOSubs{
+			ServiceName: name,
+			Price:       600,
+			UserId:      testUser,
+			StartDate:   "01-2025",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sub.ServiceName != "Netflix" || sub.CatalogID == nil || sub.Category != "entertainment" {
+			t.Fatalf("expected %q to resolve to catalog entry, got %+v", name, sub)
+		}
+	}
+
+}
+
+func TestLinkCatalog_Unit(t *testing.T) {
+
+	start, _ := time.Parse("01-2006", "01-2025")
+	repo := &fakeRepo{}
+	// Подписки, созданные до появления записи справочника
+	for i, name := range []string{"netflix ", "NETFLIX Premium", "Netflx", "Spotify"} {
+		repo.subs = append(repo.subs, model.Subscription{
+			ID: strconv.Itoa(i), ServiceName: name, Price: 600, UserId: testUser, StartDate: model.CustomDate{Time: start},
+		})
+	}
+	s := service.NewService(repo, &fakePublisher{})
+
+	linked, err := s.LinkCatalog(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if linked != 3 {
+		t.Fatalf("expected 3 linked subscriptions, got %d", linked)
+	}
+	for _, sub := range repo.subs[:3] {
+		if sub.ServiceName != "Netflix" || sub.CatalogID == nil || *sub.CatalogID != "1" || sub.Category != "entertainment" {
+			t.Fatalf("expected subscription linked to the catalog, got %+v", sub)
+		}
+	}
+	if sub := repo.subs[3]; sub.CatalogID != nil || sub.ServiceName != "Spotify" {
+		t.Fatalf("unexpected link of unknown service: %+v", sub)
+	}
+}
+
+func TestChangePlanSum_Unit(t *testing.T) {
+
+	start, _ := time.Parse("01-2006", "01-2025")
+	catalogID := "1"
+	repo := &fakeRepo{
+		subs: []model.Subscription{{
+			ID:          "1",
+			ServiceName: "Spotify",
+			Price:       200,
+			UserId:      testUser,
+			StartDate:   model.CustomDate{Time: start},
+			CatalogID:   &catalogID,
+		}},
+		plans: []model.Plan{{ID: "a37a0327-99af-4e62-8b33-55dc3863cdc7", CatalogID: catalogID, Name: "Family", Price: 500}},
+	}
+	s := service.NewService(repo, &fakePublisher{})
+
+	sub, err := s.ChangePlan(context.Background(), "1", datatransfer.DTOChangePlan{
+		PlanID:        "a37a0327-99af-4e62-8b33-55dc3863cdc7",
+		EffectiveDate: "04-2025",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo.subs[0] = sub
+
+	to, _ := time.Parse("01-2006", "06-2025")
+	sum, err := s.Sum(context.Background(), model.Filter{}, start, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Net != 3*200+3*500 {
+		t.Fatalf("expected sum %d, got %d", 3*200+3*500, sum.Net)
+	}
+
+}
+
+func TestAddPriceSum_Unit(t *testing.T) {
+
+	start, _ := time.Parse("01-2006", "01-2024")
+	repo := &fakeRepo{subs: []model.Subscription{{
+		ID:          "1",
+		ServiceName: "Netflix",
+		Price:       500,
+		UserId:      testUser,
+		StartDate:   model.CustomDate{Time: start},
+	}}}
+	s := service.NewService(repo, &fakePublisher{})
+
+	if _, err := s.AddPrice(context.Background(), "1", datatransfer.DTOPrice{Price: 700, EffectiveFrom: "07-2024"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddPrice(context.Background(), "1", datatransfer.DTOPrice{Price: 700, EffectiveFrom: "12-2023"}); err == nil {
+		t.Fatal("expected error for price before subscription start")
+	}
+
+	to, _ := time.Parse("01-2006", "12-2024")
+	sum, err := s.Sum(context.Background(), model.Filter{}, start, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Net != 6*500+6*700 {
+		t.Fatalf("expected sum %d, got %d", 6*500+6*700, sum.Net)
+	}
+
+}
+
+func TestBalancesDiscount_Unit(t *testing.T) {
+
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+	ctx := context.Background()
+
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	household := "0c7d1f7e-3b5a-4e8d-9f21-6a4b2c8d1e05"
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       300,
+		UserId:      testUser,
+		StartDate:   "01-2025",
+		HouseholdID: household,
+		SplitType:   "fixed",
+		Members:     []datatransfer.DTOMember{{UserId: testUser, Share: 200}, {UserId: partner, Share: 100}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 3 месяца за полцены: участник должен половину своей доли
+	if _, err := s.AddDiscount(ctx, sub.ID, datatransfer.DTODiscount{Type: "percentage", Value: 50, StartDate: "01-2025", EndDate: "03-2025"}); err != nil {
+		t.Fatal(err)
+	}
+
+	from, _ := time.Parse("01-2006", "01-2025")
+	to, _ := time.Parse("01-2006", "04-2025")
+	balances, err := s.Balances(ctx, household, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := s.Sum(ctx, model.Filter{UserId: partner}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := 3*50 + 100
+	if balances.Net[partner] != -want || balances.Net[testUser] != want || member.Net != want {
+		t.Fatalf("expected partner to owe %d, got balances %v and net share %d", want, balances.Net, member.Net)
+	}
+	if len(balances.Debts) != 1 || balances.Debts[0].From != partner || balances.Debts[0].Amount != want {
+		t.Fatalf("unexpected debts: %+v", balances.Debts)
+	}
+}
+
+func TestFixedSplitPriceChange_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	start, _ := time.Parse("01-2006", "01-2024")
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	repo := &fakeRepo{subs: []model.Subscription{{
+		ID:          "1",
+		ServiceName: "Netflix",
+		Price:       300,
+		UserId:      testUser,
+		StartDate:   model.CustomDate{Time: start},
+		SplitType:   model.SplitFixed,
+		Members:     []model.Member{{UserId: testUser, Share: 200}, {UserId: partner, Share: 100}},
+	}}}
+	s := service.NewService(repo, &fakePublisher{})
+
+	if _, err := s.AddPrice(ctx, "1", datatransfer.DTOPrice{Price: 600, EffectiveFrom: "07-2024"}); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().AddDate(1, 0, 0).Format("01-2006")
+	if _, err := s.AddPrice(ctx, "1", datatransfer.DTOPrice{Price: 900, EffectiveFrom: future}); err != nil {
+		t.Fatal(err)
+	}
+	sub, err := s.GetInfo(ctx, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Price != 600 {
+		t.Fatalf("expected current price 600 until the future price takes effect, got %d", sub.Price)
+	}
+
+	to, _ := time.Parse("01-2006", "12-2024")
+	for userId, want := range map[string]int{testUser: 6*200 + 6*400, partner: 6*100 + 6*200} {
+		sum, err := s.Sum(ctx, model.Filter{UserId: userId}, start, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum.Net != want {
+			t.Fatalf("%s: expected share %d after price change, got %d", userId, want, sum.Net)
+		}
+	}
+
+}
+
+func TestPauseResumeSum_Unit(t *testing.T) {
+
+	start, _ := time.Parse("01-2006", "01-2025")
+	repo := &fakeRepo{subs: []model.Subscription{{
+		ID:          "1",
+		ServiceName: "Netflix",
+		Price:       100,
+		UserId:      testUser,
+		StartDate:   model.CustomDate{Time: start},
+		Status:      model.StatusActive,
+	}}}
+	s := service.NewService(repo, &fakePublisher{})
+	ctx := context.Background()
+
+	if _, err := s.Resume(ctx, "1", datatransfer.DTOStatusChange{EffectiveDate: "02-2025"}); err == nil {
+		t.Fatal("expected error resuming active subscription")
+	}
+	if _, err := s.Pause(ctx, "1", datatransfer.DTOStatusChange{EffectiveDate: "03-2025"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Resume(ctx, "1", datatransfer.DTOStatusChange{EffectiveDate: "06-2025"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Cancel(ctx, "1", datatransfer.DTOStatusChange{Reason: "too expensive", EffectiveDate: "10-2025"}); err != nil {
+		t.Fatal(err)
+	}
+
+	to, _ := time.Parse("01-2006", "12-2025")
+	sum, err := s.Sum(ctx, model.Filter{}, start, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// январь-февраль и июнь-октябрь
+	if sum.Net != 7*100 {
+		t.Fatalf("expected sum %d, got %d", 7*100, sum.Net)
+	}
+
+}
+
+func TestTrialSum_Unit(t *testing.T) {
+
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+	ctx := context.Background()
+
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       100,
+		UserId:      testUser,
+		StartDate:   "01-2025",
+		TrialMonths: 3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.ConversionDate().Format("01-2006") != "04-2025" {
+		t.Fatalf("expected conversion in 04-2025, got %s", sub.ConversionDate().Format("01-2006"))
+	}
+
+	from, _ := time.Parse("01-2006", "01-2025")
+	to, _ := time.Parse("01-2006", "06-2025")
+	sum, err := s.Sum(ctx, model.Filter{}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Net != 3*100 {
+		t.Fatalf("expected sum %d, got %d", 3*100, sum.Net)
+	}
+
+}
+
+func TestDiscountSum_Unit(t *testing.T) {
+
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+	ctx := context.Background()
+
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       200,
+		UserId:      testUser,
+		StartDate:   "01-2025",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -50% в феврале-марте и -30 рублей с марта бессрочно
+	if _, err := s.AddDiscount(ctx, sub.ID, datatransfer.DTODiscount{Type: "percentage", Value: 50, StartDate: "02-2025", EndDate: "03-2025"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddDiscount(ctx, sub.ID, datatransfer.DTODiscount{Type: "fixed", Value: 30, StartDate: "03-2025"}); err != nil {
+		t.Fatal(err)
+	}
+
+	from, _ := time.Parse("01-2006", "01-2025")
+	to, _ := time.Parse("01-2006", "04-2025")
+	sum, err := s.Sum(ctx, model.Filter{}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Gross != 4*200 {
+		t.Fatalf("expected gross %d, got %d", 4*200, sum.Gross)
+	}
+	if want := 200 + 100 + 70 + 170; sum.Net != want {
+		t.Fatalf("expected net %d, got %d", want, sum.Net)
+	}
+
+}
+
+func TestFixedSplitDiscount_Unit(t *testing.T) {
+
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+	ctx := context.Background()
+
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       300,
+		UserId:      testUser,
+		StartDate:   "01-2025",
+		SplitType:   "fixed",
+		Members:     []datatransfer.DTOMember{{UserId: testUser, Share: 200}, {UserId: partner, Share: 100}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// -50% в феврале: доли участников уменьшаются в том же отношении
+	if _, err := s.AddDiscount(ctx, sub.ID, datatransfer.DTODiscount{Type: "percentage", Value: 50, StartDate: "02-2025", EndDate: "02-2025"}); err != nil {
+		t.Fatal(err)
+	}
+
+	from, _ := time.Parse("01-2006", "01-2025")
+	to, _ := time.Parse("01-2006", "02-2025")
+	owner, err := s.Sum(ctx, model.Filter{UserId: testUser}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	member, err := s.Sum(ctx, model.Filter{UserId: partner}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, err := s.Sum(ctx, model.Filter{}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner.Net != 200+100 || member.Net != 100+50 {
+		t.Fatalf("expected net shares 300 and 150, got %d and %d", owner.Net, member.Net)
+	}
+	if owner.Net+member.Net != all.Net {
+		t.Fatalf("member shares %d + %d do not add up to net total %d", owner.Net, member.Net, all.Net)
+	}
+
+}
+
+type fakeNotifier struct {
+	fail bool
+	sent []model.Notification
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, msg model.Notification) error {
+	if f.fail {
+		return errors.New("delivery failed")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestSendReminders_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	month := model.MonthStart(time.Now())
+	// Ближайшее списание всегда попадает в окно 31 день, а окончание подписки — еще нет
+	repo := &fakeRepo{subs: []model.Subscription{{
+		ID:          "1",
+		ServiceName: "Netflix",
+		Price:       100,
+		UserId:      testUser,
+		StartDate:   model.CustomDate{Time: month.AddDate(0, -3, 0)},
+		EndDate:     &model.CustomDate{Time: month.AddDate(0, 2, 0)},
+		Reminders:   []model.ReminderSetting{{ID: "r1", SubscriptionID: "1", Channel: model.ChannelLog, LeadDays: 31}},
+	}}}
+	s := service.NewService(repo, &fakePublisher{})
+	notifier := &fakeNotifier{fail: true}
+	s.RegisterNotifier(model.ChannelLog, notifier)
+
+	if _, err := s.SendReminders(ctx); err == nil {
+		t.Fatal("expected delivery error")
+	}
+
+	notifier.fail = false
+	sent, err := s.SendReminders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != 1 || len(notifier.sent) != 1 {
+		t.Fatalf("expected one reminder after failed delivery, got %d", sent)
+	}
+	if r, _ := notifier.sent[0].Data.(model.Reminder); r.Kind != model.ReminderRenewal || r.Amount != 100 {
+		t.Fatalf("expected renewal reminder for 100, got %+v", notifier.sent[0])
+	}
+
+	if sent, err := s.SendReminders(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected no duplicate reminders, sent=%d err=%v", sent, err)
+	}
+}
+
+func TestDigest_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	month := func(s string) model.CustomDate {
+		m, _ := time.Parse("01-2006", s)
+		return model.CustomDate{Time: m}
+	}
+	end := month("03-2025")
+	repo := &fakeRepo{subs: []model.Subscription{
+		{ID: "1", ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: month("01-2025")},
+		{ID: "2", ServiceName: "Spotify", Price: 50, UserId: testUser, StartDate: month("03-2025")},
+		{ID: "3", ServiceName: "Okko", Price: 30, UserId: testUser, StartDate: month("01-2025"), EndDate: &end},
+	}}
+	s := service.NewService(repo, &fakePublisher{})
+
+	d, err := s.Digest(ctx, testUser, model.DigestMonth, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Total != 180 || d.PreviousTotal != 130 || d.Change != 50 {
+		t.Fatalf("expected totals 180/130/+50, got %d/%d/%+d", d.Total, d.PreviousTotal, d.Change)
+	}
+	if len(d.Active) != 3 || len(d.New) != 1 || d.New[0].ID != "2" || len(d.Ended) != 1 || d.Ended[0].ID != "3" {
+		t.Fatalf("unexpected active/new/ended: %d/%v/%v", len(d.Active), d.New, d.Ended)
+	}
+	if len(d.Upcoming) != 2 || d.Upcoming[0].Month.Format("01-2006") != "04-2025" {
+		t.Fatalf("expected two charges in 04-2025, got %v", d.Upcoming)
+	}
+
+	text, err := report.DigestText(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text, "Your monthly subscription digest, 01.03.2025 - 31.03.2025") || !strings.Contains(text, "Spent: 180 RUB (previous month: 130 RUB, +50 RUB)") {
+		t.Fatalf("unexpected digest text:\n%s", text)
+	}
+	if _, err := report.DigestHTML(d); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExpireSubscriptions_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	events := &fakePublisher{}
+	s := service.NewService(repo, events)
+
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       100,
+		UserId:      testUser,
+		StartDate:   "01-2025",
+		EndDate:     "03-2025",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := s.ExpireSubscriptions(ctx); err != nil || n != 1 {
+		t.Fatalf("expected one expired subscription, got %d: %v", n, err)
+	}
+	if n, err := s.ExpireSubscriptions(ctx); err != nil || n != 0 {
+		t.Fatalf("expected expiry to be idempotent, got %d: %v", n, err)
+	}
+
+	expired, _ := repo.GetByID(ctx, sub.ID)
+	if expired.Status != model.StatusExpired {
+		t.Fatalf("expected status expired, got %s", expired.Status)
+	}
+	if last := events.events[len(events.events)-1]; last.Type != model.EventExpired {
+		t.Fatalf("expected %s event, got %s", model.EventExpired, last.Type)
+	}
+
+	from, _ := time.Parse("01-2006", "01-2025")
+	to, _ := time.Parse("01-2006", "12-2025")
+	sum, err := s.Sum(ctx, model.Filter{}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum.Net != 3*100 {
+		t.Fatalf("expected expired subscription in historical sum %d, got %d", 3*100, sum.Net)
+	}
+}
+
+func TestExpireCancelled_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       100,
+		UserId:      testUser,
+		StartDate:   "01-2025",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Cancel(ctx, sub.ID, datatransfer.DTOStatusChange{EffectiveDate: "03-2025"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if n, err := s.ExpireSubscriptions(ctx); err != nil || n != 1 {
+		t.Fatalf("expected cancelled subscription to expire, got %d: %v", n, err)
+	}
+	expired, _ := repo.GetByID(ctx, sub.ID)
+	if expired.Status != model.StatusExpired {
+		t.Fatalf("expected status expired, got %s", expired.Status)
+	}
+}
+
+func TestForecast_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	now := model.MonthStart(time.Now())
+	// Ежемесячная подписка с пробным периодом в текущем месяце и годовая, оплаченная в прошлом месяце
+	if _, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix",
+		Price:       100,
+		UserId:      testUser,
+		StartDate:   now.Format("01-2006"),
+		TrialMonths: 1,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	yearly, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName:  "iCloud",
+		Price:        1200,
+		UserId:       testUser,
+		StartDate:    now.AddDate(0, -1, 0).Format("01-2006"),
+		BillingCycle: "yearly",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// С пятого месяца прогноза годовая подписка дорожает, но следующее списание только через год
+	if _, err := s.AddPrice(ctx, yearly.ID, datatransfer.DTOPrice{Price: 1500, EffectiveFrom: now.AddDate(0, 4, 0).Format("01-2006")}); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := s.Forecast(ctx, testUser, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Months) != 12 {
+		t.Fatalf("expected 12 months, got %d", len(f.Months))
+	}
+	if f.Months[0].Amount != 0 || f.Months[1].Amount != 100 {
+		t.Fatalf("expected trial month free and then 100, got %d and %d", f.Months[0].Amount, f.Months[1].Amount)
+	}
+	if f.Months[11].Amount != 100+1500 {
+		t.Fatalf("expected yearly renewal at new price in last month, got %d", f.Months[11].Amount)
+	}
+	if want := 11*100 + 1500; f.Total.Net != want || f.Months[11].Cumulative != want {
+		t.Fatalf("expected total %d, got %d (cumulative %d)", want, f.Total.Net, f.Months[11].Cumulative)
+	}
+}
+
+func TestCompare_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	netflix, err := s.Create(ctx, datatransfer.DTOSubs{ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: "01-2024"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddPrice(ctx, netflix.ID, datatransfer.DTOPrice{Price: 150, EffectiveFrom: "04-2024"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Create(ctx, datatransfer.DTOSubs{ServiceName: "Spotify", Price: 60, UserId: testUser, StartDate: "05-2024"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Второй квартал против первого
+	from := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	prevFrom, prevTo := model.ComparePrevious.Window(from, to)
+	if prevFrom.Month() != time.January || prevTo.Month() != time.March {
+		t.Fatalf("expected previous quarter, got %s - %s", prevFrom, prevTo)
+	}
+
+	c, err := s.Compare(ctx, model.Filter{UserId: testUser}, from, to, prevFrom, prevTo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Current.Total.Net != 570 || c.Previous.Total.Net != 300 || c.Delta != 270 {
+		t.Fatalf("expected 570 vs 300, got %d vs %d (delta %d)", c.Current.Total.Net, c.Previous.Total.Net, c.Delta)
+	}
+	if c.DeltaPercent == nil || *c.DeltaPercent != 90 {
+		t.Fatalf("expected +90%%, got %v", c.DeltaPercent)
+	}
+	// Сервисы упорядочены по вкладу в изменение: подорожание Netflix больше, чем новый Spotify
+	if len(c.Services) != 2 || c.Services[0].ServiceName != "Netflix" || c.Services[0].Delta != 150 || *c.Services[0].DeltaPercent != 50 {
+		t.Fatalf("expected Netflix +150 (+50%%) first, got %+v", c.Services)
+	}
+	if c.Services[1].ServiceName != "Spotify" || c.Services[1].Delta != 120 || c.Services[1].DeltaPercent != nil {
+		t.Fatalf("expected new Spotify +120 without percentage, got %+v", c.Services[1])
+	}
+}
+
+func TestAggregate_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: "01-2024"},
+		{ServiceName: "Spotify", Price: 50, UserId: testUser, StartDate: "02-2024"},
+	} {
+		if _, err := s.Create(ctx, dto); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rows, err := s.Aggregate(ctx, model.Filter{}, from, to,
+		[]model.Dimension{model.DimService}, []model.Metric{model.MetricSum, model.MetricCount, model.MetricAvg})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].ServiceName != "Netflix" || *rows[0].Sum != 300 || *rows[0].Count != 3 || *rows[0].Avg != 100 {
+		t.Fatalf("unexpected Netflix row: %+v", rows)
+	}
+	if rows[1].Month != nil || rows[1].UserId != "" || *rows[1].Sum != 100 || *rows[1].Count != 2 {
+		t.Fatalf("unexpected Spotify row: %+v", rows[1])
+	}
+
+	// Группировка по месяцам с одной метрикой
+	rows, err = s.Aggregate(ctx, model.Filter{}, from, to, []model.Dimension{model.DimMonth}, []model.Metric{model.MetricSum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || *rows[0].Sum != 100 || *rows[2].Sum != 150 || rows[0].Count != nil {
+		t.Fatalf("unexpected monthly rows: %+v", rows)
+	}
+
+	// Совместная подписка делится между участниками
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	if _, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "YouTube", Price: 90, UserId: testUser, StartDate: "01-2024",
+		SplitType: "percentage",
+		Members:   []datatransfer.DTOMember{{UserId: testUser, Share: 50}, {UserId: partner, Share: 50}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rows, err = s.Aggregate(ctx, model.Filter{}, from, to, []model.Dimension{model.DimUser}, []model.Metric{model.MetricSum})
+	if err != nil {
+		t.Fatal(err)
+	}
+	byUser := make(map[string]int)
+	for _, row := range rows {
+		byUser[row.UserId] = *row.Sum
+	}
+	if byUser[testUser] != 300+100+3*45 || byUser[partner] != 3*45 {
+		t.Fatalf("expected shared subscription split between members, got %v", byUser)
+	}
+}
+
+func TestInsights_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Netflix", Price: 500, UserId: testUser, StartDate: "01-2024", Category: "video"},
+		{ServiceName: "  netflix ", Price: 300, UserId: testUser, StartDate: "06-2024", Category: "video"},
+		// Давно закончившаяся подписка не учитывается
+		{ServiceName: "Netflix", Price: 400, UserId: testUser, StartDate: "01-2019", EndDate: "12-2020", Category: "video"},
+		{ServiceName: "Spotify", Price: 200, UserId: testUser, StartDate: "01-2024", Category: "music"},
+		{ServiceName: "Yandex Music", Price: 150, UserId: testUser, StartDate: "01-2024", Category: "music"},
+		// Годовая подписка стоит 1200 / 12 = 100 в месяц
+		{ServiceName: "iCloud", Price: 1200, UserId: testUser, StartDate: "01-2024", Category: "storage", BillingCycle: "yearly"},
+	} {
+		if _, err := s.Create(ctx, dto); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	insights, err := s.Insights(ctx, testUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(insights.Findings) != 2 {
+		t.Fatalf("expected 2 findings, got %+v", insights.Findings)
+	}
+	dup, category := insights.Findings[0], insights.Findings[1]
+	if dup.Kind != model.InsightDuplicate || dup.Key != "netflix" || len(dup.SubscriptionIDs) != 2 || dup.EstimatedSavings != 300 {
+		t.Fatalf("unexpected duplicate finding: %+v", dup)
+	}
+	if category.Kind != model.InsightSameCategory || category.Key != "music" || category.MonthlyCost != 350 || category.EstimatedSavings != 150 {
+		t.Fatalf("unexpected category finding: %+v", category)
+	}
+	if insights.EstimatedSavings != 450 {
+		t.Fatalf("expected total savings 450, got %d", insights.EstimatedSavings)
+	}
+}
+
+func TestUnused_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	start := model.MonthStart(time.Now()).AddDate(0, -2, 0).Format("01-2006")
+	ids := make(map[string]string)
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Slack", Price: 300, UserId: testUser, StartDate: start},
+		{ServiceName: "Notion", Price: 100, UserId: testUser, StartDate: start},
+		{ServiceName: "Figma", Price: 500, UserId: testUser, StartDate: start},
+	} {
+		sub, err := s.Create(ctx, dto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[sub.ServiceName] = sub.ID
+	}
+
+	// Slack открывали четыре раза больше месяца назад, Notion — только что, Figma — никогда
+	usedAt := time.Now().AddDate(0, 0, -40).Format(time.RFC3339)
+	if _, err := s.RecordUsage(ctx, ids["Slack"], datatransfer.DTOUsage{UsedAt: usedAt, Count: 4}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.RecordUsage(ctx, ids["Notion"], datatransfer.DTOUsage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	unused, err := s.Unused(ctx, testUser, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(unused) != 2 || unused[0].ServiceName != "Figma" || unused[1].ServiceName != "Slack" {
+		t.Fatalf("expected Figma and Slack, got %+v", unused)
+	}
+	if unused[0].CostPerUse != nil || unused[0].TotalCost != 1500 {
+		t.Fatalf("expected Figma without usages and 1500 paid, got %+v", unused[0])
+	}
+	if unused[1].UseCount != 4 || unused[1].CostPerUse == nil || *unused[1].CostPerUse != 225 || unused[1].DaysUnused < 39 {
+		t.Fatalf("expected Slack at 225 per use, got %+v", unused[1])
+	}
+}
+
+func TestSimulate_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	now := model.MonthStart(time.Now())
+	start := now.AddDate(-1, 0, 0).Format("01-2006")
+	netflix, err := s.Create(ctx, datatransfer.DTOSubs{ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spotify, err := s.Create(ctx, datatransfer.DTOSubs{ServiceName: "Spotify", Price: 50, UserId: testUser, StartDate: start})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sim, err := s.Simulate(ctx, testUser, datatransfer.DTOSimulation{Changes: []datatransfer.DTOSimulateChange{
+		{Action: "cancel", SubscriptionID: spotify.ID, From: now.AddDate(0, 3, 0).Format("01-2006")},
+		{Action: "change_price", SubscriptionID: netflix.ID, Price: 150, From: now.AddDate(0, 6, 0).Format("01-2006")},
+		{Action: "add", Subscription: &datatransfer.DTOSubs{ServiceName: "iCloud", Price: 30, StartDate: now.Format("01-2006")}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sim.Months) != 12 || sim.Baseline.Net != 12*150 {
+		t.Fatalf("expected 12 months baseline 1800, got %d months and %d", len(sim.Months), sim.Baseline.Net)
+	}
+	// Как и при настоящей отмене, месяц отмены еще оплачивается
+	if want := 6*100 + 6*150 + 4*50 + 12*30; sim.Scenario.Net != want || sim.Delta != want-1800 {
+		t.Fatalf("expected scenario %d, got %d (delta %d)", want, sim.Scenario.Net, sim.Delta)
+	}
+	if sim.Months[3].Scenario != 100+50+30 || sim.Months[4].Scenario != 100+30 || sim.Months[4].Delta != -20 {
+		t.Fatalf("unexpected months around cancellation: %+v %+v", sim.Months[3], sim.Months[4])
+	}
+
+	// Ничего не сохраняется
+	if sub, _ := repo.GetByID(ctx, netflix.ID); sub.Price != 100 || len(sub.Prices) != 0 {
+		t.Fatalf("simulation changed stored prices: %+v", sub)
+	}
+	if sub, _ := repo.GetByID(ctx, spotify.ID); sub.EndDate != nil || sub.Status != model.StatusActive || len(sub.StatusHistory) != 0 {
+		t.Fatalf("simulation changed stored end date: %+v", sub)
+	}
+	if len(repo.subs) != 2 {
+		t.Fatalf("simulation stored a new subscription")
+	}
+
+	_, err = s.Simulate(ctx, testUser, datatransfer.DTOSimulation{Changes: []datatransfer.DTOSimulateChange{
+		{Action: "cancel", SubscriptionID: "7b0e7c8e-4c7a-4a43-9d1d-2f1c3a1b9e00"},
+	}})
+	if !errors.Is(err, model.ErrSimulationSubscription) {
+		t.Fatalf("expected unknown subscription error, got %v", err)
+	}
+}
+
+func TestReconcile_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	ids := make(map[string]string)
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: "01-2024", EndDate: "02-2024"},
+		{ServiceName: "Spotify", Price: 50, UserId: testUser, StartDate: "01-2024"},
+		{ServiceName: "iCloud", Price: 30, UserId: testUser, StartDate: "03-2024"},
+	} {
+		sub, err := s.Create(ctx, dto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[sub.ServiceName] = sub.ID
+	}
+
+	for _, p := range []struct {
+		service string
+		dto     datatransfer.DTOPayment
+	}{
+		{"Netflix", datatransfer.DTOPayment{Amount: 100, PaidAt: "2024-01-05"}},
+		{"Netflix", datatransfer.DTOPayment{Amount: 80, PaidAt: "2024-02-05"}},
+		// Подписка закончилась в феврале, мартовский платеж неожиданный
+		{"Netflix", datatransfer.DTOPayment{Amount: 100, PaidAt: "2024-03-05"}},
+		{"Spotify", datatransfer.DTOPayment{Amount: 50, PaidAt: "2024-01-10"}},
+		{"Spotify", datatransfer.DTOPayment{Amount: 50, PaidAt: "2024-02-10"}},
+		{"Spotify", datatransfer.DTOPayment{Amount: 50, PaidAt: "2024-02-20"}},
+		{"Spotify", datatransfer.DTOPayment{Amount: 5, Currency: "USD", PaidAt: "2024-03-10"}},
+		// Вне периода сверки
+		{"Spotify", datatransfer.DTOPayment{Amount: 50, PaidAt: "2024-04-10"}},
+	} {
+		if _, err := s.AddPayment(ctx, ids[p.service], p.dto); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	rec, err := s.Reconcile(ctx, model.Filter{UserId: testUser}, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Expected != 380 || rec.Paid != 430 || rec.Difference != 50 || len(rec.Months) != 3 {
+		t.Fatalf("expected 380 vs 430 over 3 months, got %d vs %d (%d months)", rec.Expected, rec.Paid, len(rec.Months))
+	}
+
+	statuses := make(map[string]model.ReconciliationStatus)
+	for _, m := range rec.Months {
+		for _, it := range m.Items {
+			statuses[m.Month.Format("01")+" "+it.ServiceName] = it.Status
+		}
+	}
+	for item, want := range map[string]model.ReconciliationStatus{
+		"01 Netflix": model.ReconcilePaid,
+		"02 Netflix": model.ReconcileUnderpaid,
+		"03 Netflix": model.ReconcileUnexpected,
+		"02 Spotify": model.ReconcileOverpaid,
+		"03 Spotify": model.ReconcileCurrency,
+		"03 iCloud":  model.ReconcileMissing,
+	} {
+		if statuses[item] != want {
+			t.Fatalf("%s: expected %s, got %s", item, want, statuses[item])
+		}
+	}
+}
+
+func TestImportStatement_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	if _, err := s.Create(ctx, datatransfer.DTOSubs{ServiceName: "Spotify", Price: 199, UserId: testUser, StartDate: "01-2024"}); err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(month, d int) time.Time { return time.Date(2024, time.Month(month), d, 0, 0, 0, 0, time.UTC) }
+	txns := []model.Transaction{
+		{Date: day(1, 5), Amount: 799, Description: "NETFLIX.COM 866-579"},
+		{Date: day(2, 5), Amount: 799, Description: "NETFLIX.COM 866-579"},
+		{Date: day(3, 6), Amount: 799, Description: "NETFLIX.COM 866-580"},
+		// Уже есть подписка
+		{Date: day(1, 10), Amount: 199, Description: "SPOTIFY P1A2B3"},
+		{Date: day(2, 10), Amount: 199, Description: "SPOTIFY P4C5D6"},
+		{Date: day(3, 10), Amount: 199, Description: "SPOTIFY P7E8F9"},
+		// Совпадает с существующим названием сервиса
+		{Date: day(1, 15), Amount: 299, Description: "ЯНДЕКС*ПЛЮС 4421"},
+		{Date: day(2, 15), Amount: 299, Description: "ЯНДЕКС ПЛЮС"},
+		{Date: day(3, 15), Amount: 299, Description: "ЯНДЕКС ПЛЮС"},
+		// Годовая подписка, которой нет ни в справочнике, ни среди сервисов
+		{Date: day(1, 20), Amount: 2990, Description: "JetBrains s.r.o."},
+		{Date: time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC), Amount: 3190, Description: "JETBRAINS S.R.O."},
+		// Разовые покупки с разными суммами
+		{Date: day(1, 3), Amount: 1540, Description: "ПЕРЕКРЕСТОК"},
+		{Date: day(2, 2), Amount: 320, Description: "ПЕРЕКРЕСТОК"},
+		{Date: day(3, 4), Amount: 2780, Description: "ПЕРЕКРЕСТОК"},
+	}
+
+	proposals, err := s.ImportStatement(ctx, testUser, txns)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := make(map[string]model.Proposal)
+	for _, p := range proposals {
+		byName[p.ServiceName] = p
+	}
+	if len(proposals) != 3 {
+		t.Fatalf("expected 3 proposals, got %+v", proposals)
+	}
+	if p := byName["Netflix"]; !p.Matched || p.Price != 799 || p.BillingCycle != model.CycleMonthly || p.Occurrences != 3 {
+		t.Fatalf("unexpected Netflix proposal: %+v", p)
+	}
+	if p := byName["Jetbrains"]; p.Matched || p.BillingCycle != model.CycleYearly || p.Price != 3190 {
+		t.Fatalf("unexpected yearly proposal: %+v", proposals)
+	}
+
+	if p, ok := byName["Яндекс Плюс"]; !ok || !p.Matched {
+		t.Fatalf("expected proposal matched to existing service name, got %+v", proposals)
+	}
+
+	// Сбой при сохранении не оставляет ни созданных подписок, ни подтвержденных предложений
+	repo.failConfirm = true
+	stored := len(repo.subs)
+	ids := []string{byName["Netflix"].ID, byName["Jetbrains"].ID}
+	if _, err := s.ConfirmProposals(ctx, testUser, datatransfer.DTOConfirmProposals{IDs: ids}); err == nil {
+		t.Fatal("expected confirmation to fail")
+	}
+	if pending, _ := repo.ListProposals(ctx, testUser); len(pending) != 3 || len(repo.subs) != stored {
+		t.Fatalf("failed confirmation left changes: %d pending, %d subscriptions", len(pending), len(repo.subs)-stored)
+	}
+	repo.failConfirm = false
+
+	subs, err := s.ConfirmProposals(ctx, testUser, datatransfer.DTOConfirmProposals{IDs: []string{byName["Netflix"].ID}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs) != 1 || subs[0].ServiceName != "Netflix" || subs[0].Price != 799 || subs[0].StartDate.Month() != time.January {
+		t.Fatalf("unexpected confirmed subscription: %+v", subs)
+	}
+	if _, err := s.ConfirmProposals(ctx, testUser, datatransfer.DTOConfirmProposals{IDs: []string{byName["Netflix"].ID}}); !errors.Is(err, model.ErrProposalNotPending) {
+		t.Fatalf("expected second confirmation to fail, got %v", err)
+	}
+}
+
+func TestJournal_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	ids := make(map[string]string)
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: "01-2024", Category: "video"},
+		{ServiceName: "Spotify", Price: 50, UserId: testUser, StartDate: "02-2024", Category: "music"},
+	} {
+		sub, err := s.Create(ctx, dto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids[sub.ServiceName] = sub.ID
+	}
+	if _, err := s.SetJournalAccounts(ctx, testUser, datatransfer.DTOJournalAccounts{
+		Funding:    "Assets:Bank:Card",
+		Categories: map[string]string{"Music": "Expenses:Fun:Music"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	journal, err := s.Journal(ctx, testUser, from, to, model.JournalLedger, model.JournalExpected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(journal.Entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(journal.Entries))
+	}
+	for _, e := range journal.Entries {
+		want := "Expenses:Subscriptions:Video"
+		if e.Payee == "Spotify" {
+			want = "Expenses:Fun:Music"
+		}
+		if e.Account != want || e.Funding != "Assets:Bank:Card" {
+			t.Fatalf("unexpected accounts for %s: %s / %s", e.Payee, e.Account, e.Funding)
+		}
+	}
+	if !journal.To.Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected period to end on 2024-02-29, got %s", journal.To)
+	}
+
+	if _, err := s.AddPayment(ctx, ids["Spotify"], datatransfer.DTOPayment{Amount: 55, PaidAt: "2024-02-10"}); err != nil {
+		t.Fatal(err)
+	}
+	actual, err := s.Journal(ctx, testUser, from, to, model.JournalBeancount, model.JournalActual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(actual.Entries) != 1 || actual.Entries[0].Amount != 55 || actual.Entries[0].Payee != "Spotify" {
+		t.Fatalf("expected a single Spotify payment of 55, got %+v", actual.Entries)
+	}
+
+	// Платеж по совместной подписке целиком попадает в проводки владельца, который его оплатил
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	youtube, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "YouTube", Price: 90, UserId: testUser, StartDate: "01-2024",
+		SplitType: "percentage",
+		Members:   []datatransfer.DTOMember{{UserId: testUser, Share: 60}, {UserId: partner, Share: 40}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddPayment(ctx, youtube.ID, datatransfer.DTOPayment{Amount: 90, PaidAt: "2024-02-15"}); err != nil {
+		t.Fatal(err)
+	}
+	for user, want := range map[string][]int{testUser: {55, 90}, partner: nil} {
+		actual, err := s.Journal(ctx, user, from, to, model.JournalLedger, model.JournalActual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var amounts []int
+		for _, e := range actual.Entries {
+			amounts = append(amounts, e.Amount)
+		}
+		if !slices.Equal(amounts, want) {
+			t.Fatalf("expected %s payments %v, got %v", user, want, amounts)
+		}
+	}
+}
+
+func TestBillingStatement_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	for _, dto := range []datatransfer.DTOSubs{
+		{ServiceName: "Spotify", Price: 50, UserId: testUser, StartDate: "01-2024", EndDate: "02-2024"},
+		{ServiceName: "Netflix", Price: 100, UserId: testUser, StartDate: "02-2024"},
+		{ServiceName: "iCloud", Price: 30, UserId: testUser, StartDate: "06-2024"},
+	} {
+		if _, err := s.Create(ctx, dto); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
+	statement, err := s.BillingStatement(ctx, testUser, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statement.Total != 400 || len(statement.Lines) != 2 {
+		t.Fatalf("expected 400 over 2 lines, got %d over %d", statement.Total, len(statement.Lines))
+	}
+	netflix := statement.Lines[0]
+	if netflix.ServiceName != "Netflix" || netflix.Amount != 300 || len(netflix.Months) != 3 {
+		t.Fatalf("expected Netflix billed 300 for 3 months first, got %+v", netflix)
+	}
+	if ranges := netflix.MonthRanges(); len(ranges) != 1 || !ranges[0][1].Equal(to) {
+		t.Fatalf("expected a single range ending in April, got %v", ranges)
+	}
+}
+
+func TestUpdateKeepsOmittedFields_Unit(t *testing.T) {
+
+	ctx := context.Background()
+	repo := &fakeRepo{}
+	s := service.NewService(repo, &fakePublisher{})
+
+	const partner = "5b1f3c1e-8a4d-4f2b-9c7e-2d6a1b0e9f34"
+	household := "0c7d1f7e-3b5a-4e8d-9f21-6a4b2c8d1e05"
+	sub, err := s.Create(ctx, datatransfer.DTOSubs{
+		ServiceName: "Netflix", Price: 300, UserId: testUser, StartDate: "01-2024",
+		HouseholdID: household, SplitType: "percentage",
+		Members:  []datatransfer.DTOMember{{UserId: testUser, Share: 50}, {UserId: partner, Share: 50}},
+		Category: "video", Tags: []string{"family"}, BillingCycle: "quarterly",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Тело из одних названия и цены ничего не сбрасывает
+	updated, err := s.Update(ctx, sub.ID, datatransfer.DTOSubs{ServiceName: "Netflix", Price: 300})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.HouseholdID == nil || *updated.HouseholdID != household || updated.SplitType != model.SplitPercentage ||
+		len(updated.Members) != 2 || updated.Category != "video" || !slices.Equal(updated.Tags, []string{"family"}) ||
+		updated.BillingCycle != model.CycleQuarterly {
+		t.Fatalf("update dropped omitted fields: %+v", updated)
+	}
+
+	// Пустые списки очищают участников и теги
+	updated, err = s.Update(ctx, sub.ID, datatransfer.DTOSubs{ServiceName: "Netflix", Price: 300, Members: []datatransfer.DTOMember{}, Tags: []string{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated.Members) != 0 || updated.SplitType != model.SplitEqual || len(updated.Tags) != 0 || updated.Category != "video" {
+		t.Fatalf("expected members and tags cleared, got %+v", updated)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"slices"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+// Simulate считает прогноз расходов пользователя на горизонте с гипотетическими изменениями
+// и без них. Изменения применяются к копиям подписок и ничего не сохраняют.
+func (s *ServiceStore) Simulate(ctx context.Context, userId string, dto datatransfer.DTOSimulation) (model.Simulation, error) {
+
+	months := dto.Months
+	if months == 0 {
+		months = 12
+	}
+	from := model.MonthStart(time.Now())
+	to := from.AddDate(0, months-1, 0)
+
+	subs, err := s.listForPeriod(ctx, model.Filter{UserId: userId}, from, to)
+	if err != nil {
+		return model.Simulation{}, err
+	}
+	scenario, err := s.applyChanges(ctx, subs, userId, from, dto.Changes)
+	if err != nil {
+		return model.Simulation{}, err
+	}
+
+	return simulation(forecast(subs, userId, from, to), forecast(scenario, userId, from, to)), nil
+}
+
+// applyChanges возвращает копию подписок с примененными изменениями
+func (s *ServiceStore) applyChanges(ctx context.Context, subs []model.Subscription, userId string, now time.Time, changes []datatransfer.DTOSimulateChange) ([]model.Subscription, error) {
+	result := append([]model.Subscription(nil), subs...)
+
+	for _, change := range changes {
+		if model.SimulateAction(change.Action) == model.SimulateAdd {
+			dto := *change.Subscription
+			dto.UserId = userId
+			sub, err := model.NewSubscription(dto)
+			if err != nil {
+				return nil, err
+			}
+			if err := s.applyPlan(ctx, &sub); err != nil {
+				return nil, err
+			}
+			if err := s.applyCatalog(ctx, &sub); err != nil {
+				return nil, err
+			}
+			result = append(result, sub)
+			continue
+		}
+
+		i := -1
+		for j := range result {
+			if result[j].ID == change.SubscriptionID {
+				i = j
+			}
+		}
+		if i < 0 {
+			return nil, model.ErrSimulationSubscription
+		}
+
+		month := now
+		if change.From != "" {
+			t, err := time.Parse("01-2006", change.From)
+			if err != nil {
+				return nil, err
+			}
+			month = t
+		}
+
+		sub := &result[i]
+		switch model.SimulateAction(change.Action) {
+		case model.SimulateCancel:
+			// Отмена считается так же, как настоящая: месяц отмены еще оплачивается
+			sub.StatusHistory = slices.Clone(sub.StatusHistory)
+			if _, err := sub.Transition(model.StatusCancelled, "simulation", month); err != nil {
+				return nil, err
+			}
+		case model.SimulateChangePrice:
+			sub.Prices = append([]model.PricePoint(nil), sub.Prices...)
+			sub.SetPrice(month, change.Price)
+		}
+	}
+	return result, nil
+}
+
+// simulation сравнивает помесячно два прогноза на одном горизонте
+func simulation(baseline, scenario model.Forecast) model.Simulation {
+	result := model.Simulation{
+		UserId:       baseline.UserId,
+		From:         baseline.From,
+		To:           baseline.To,
+		Baseline:     baseline.Total,
+		Scenario:     scenario.Total,
+		Delta:        scenario.Total.Net - baseline.Total.Net,
+		DeltaPercent: model.PercentChange(baseline.Total.Net, scenario.Total.Net),
+		Months:       make([]model.SimulationMonth, 0, len(baseline.Months)),
+	}
+	for i, m := range baseline.Months {
+		s := scenario.Months[i]
+		result.Months = append(result.Months, model.SimulationMonth{
+			Month:    m.Month,
+			Baseline: m.Amount,
+			Scenario: s.Amount,
+			Delta:    s.Amount - m.Amount,
+		})
+	}
+	return result
+}
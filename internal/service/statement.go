@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"strings"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+)
+
+type ProposalRepository interface {
+	ReplaceProposals(ctx context.Context, userId string, proposals []model.Proposal) error
+	ListProposals(ctx context.Context, userId string) ([]model.Proposal, error)
+	ConfirmProposals(ctx context.Context, proposals []model.Proposal, subscriptions []model.Subscription) error
+	ListServiceNames(ctx context.Context) ([]string, error)
+}
+
+// ImportStatement ищет в списаниях из выписки регулярные платежи и предлагает по ним подписки.
+// Название сервиса сопоставляется со справочником и уже известными названиями сервисов;
+// сервисы, на которые у пользователя уже есть подписка, не предлагаются.
+// Новые предложения заменяют неподтвержденные предложения прошлой загрузки.
+func (s *ServiceStore) ImportStatement(ctx context.Context, userId string, txns []model.Transaction) ([]model.Proposal, error) {
+
+	catalog, err := s.catalogStore.ListCatalog(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names, err := s.proposalStore.ListServiceNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		catalog = append(catalog, model.CatalogEntry{Name: name})
+	}
+
+	subs, err := s.subscriptionStore.GetAll(ctx, model.Filter{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+	tracked := make(map[string]bool, len(subs))
+	for _, sub := range subs {
+		tracked[model.NormalizeLabel(sub.ServiceName)] = true
+	}
+
+	proposals := []model.Proposal{}
+	for _, r := range model.DetectRecurring(txns) {
+		name, matched := titleCase(r.Key), false
+		if entry, ok := model.ResolveService(catalog, r.Key); ok {
+			name, matched = entry.Name, true
+		}
+		if tracked[model.NormalizeLabel(name)] {
+			continue
+		}
+		proposals = append(proposals, model.NewProposal(userId, r, name, matched))
+	}
+
+	if err := s.proposalStore.ReplaceProposals(ctx, userId, proposals); err != nil {
+		return nil, err
+	}
+	return proposals, nil
+}
+
+func (s *ServiceStore) ListProposals(ctx context.Context, userId string) ([]model.Proposal, error) {
+
+	proposals, err := s.proposalStore.ListProposals(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	if proposals == nil {
+		return []model.Proposal{}, nil
+	}
+	return proposals, nil
+}
+
+// ConfirmProposals создает подписки по выбранным предложениям.
+// Подписки создаются атомарно: если хотя бы одно предложение не найдено среди ожидающих
+// или какую-то подписку не удалось создать, ничего не сохраняется.
+func (s *ServiceStore) ConfirmProposals(ctx context.Context, userId string, dto datatransfer.DTOConfirmProposals) ([]model.Subscription, error) {
+
+	pending, err := s.proposalStore.ListProposals(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]model.Proposal, len(pending))
+	for _, p := range pending {
+		byID[p.ID] = p
+	}
+	selected := make([]model.Proposal, 0, len(dto.IDs))
+	for _, id := range dto.IDs {
+		p, ok := byID[id]
+		if !ok {
+			return nil, model.ErrProposalNotPending
+		}
+		selected = append(selected, p)
+		delete(byID, id)
+	}
+
+	created := make([]model.Subscription, 0, len(selected))
+	for _, p := range selected {
+		sub, err := model.NewSubscription(datatransfer.DTOSubs{
+			ServiceName:  p.ServiceName,
+			Price:        p.Price,
+			UserId:       userId,
+			StartDate:    p.StartDate.Format("01-2006"),
+			BillingCycle: string(p.BillingCycle),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := s.applyPlan(ctx, &sub); err != nil {
+			return nil, err
+		}
+		if err := s.applyCatalog(ctx, &sub); err != nil {
+			return nil, err
+		}
+		created = append(created, sub)
+	}
+	if err := s.proposalStore.ConfirmProposals(ctx, selected, created); err != nil {
+		return nil, err
+	}
+	for _, sub := range created {
+		s.checkBudgets(ctx, nil, sub)
+	}
+	return created, nil
+}
+
+// titleCase делает заглавной первую букву каждого слова
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r := []rune(w)
+		words[i] = strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	return strings.Join(words, " ")
+}
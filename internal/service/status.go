@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"log"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type StatusRepository interface {
+	ChangeStatus(ctx context.Context, sub model.Subscription, change model.StatusChange) error
+}
+
+// Pause приостанавливает подписку, приостановленные месяцы не оплачиваются
+func (s *ServiceStore) Pause(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error) {
+	return s.changeStatus(ctx, id, model.StatusPaused, dto)
+}
+
+// Resume возобновляет приостановленную подписку
+func (s *ServiceStore) Resume(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error) {
+	return s.changeStatus(ctx, id, model.StatusActive, dto)
+}
+
+// Cancel отменяет подписку, месяц отмены становится последним оплачиваемым
+func (s *ServiceStore) Cancel(ctx context.Context, id string, dto datatransfer.DTOStatusChange) (model.Subscription, error) {
+	return s.changeStatus(ctx, id, model.StatusCancelled, dto)
+}
+
+func (s *ServiceStore) StatusHistory(ctx context.Context, id string) ([]model.StatusChange, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub.StatusHistory == nil {
+		return []model.StatusChange{}, nil
+	}
+	return sub.StatusHistory, nil
+}
+
+func (s *ServiceStore) changeStatus(ctx context.Context, id string, to model.Status, dto datatransfer.DTOStatusChange) (model.Subscription, error) {
+
+	sub, err := s.subscriptionStore.GetByID(ctx, id)
+	if err != nil {
+		return model.Subscription{}, err
+	}
+
+	effective := model.MonthStart(time.Now())
+	if dto.EffectiveDate != "" {
+		if effective, err = time.Parse("01-2006", dto.EffectiveDate); err != nil {
+			return model.Subscription{}, err
+		}
+	}
+
+	return sub, s.applyTransition(ctx, &sub, to, dto.Reason, effective)
+}
+
+// applyTransition проверяет и сохраняет смену статуса, затем публикует событие
+func (s *ServiceStore) applyTransition(ctx context.Context, sub *model.Subscription, to model.Status, reason string, effective time.Time) error {
+
+	before := *sub
+	change, err := sub.Transition(to, reason, effective)
+	if err != nil {
+		return err
+	}
+	if err := s.statusStore.ChangeStatus(ctx, *sub, change); err != nil {
+		return err
+	}
+	if err := s.events.Publish(ctx, model.NewEvent(model.EventStatusChanged, change)); err != nil {
+		log.Printf("failed to publish status change: id=%s: %v", sub.ID, err)
+	}
+	if to == model.StatusActive {
+		s.checkBudgets(ctx, &before, *sub)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"subscription/internal/model"
+	"time"
+)
+
+type TrialRepository interface {
+	ListTrialsEnding(ctx context.Context, userId string, from, to time.Time) ([]model.Subscription, error)
+	ListTrialsEnded(ctx context.Context, before time.Time) ([]model.Subscription, error)
+}
+
+// TrialsEnding возвращает подписки, первое платное списание после пробного периода
+// у которых наступит в ближайшие days дней
+func (s *ServiceStore) TrialsEnding(ctx context.Context, userId string, days int) ([]model.TrialEnding, error) {
+
+	now := time.Now()
+	// Списание происходит первого числа месяца, следующего за последним месяцем пробного периода
+	from := model.MonthStart(now)
+	to := model.MonthStart(now.AddDate(0, 0, days)).AddDate(0, -1, 0)
+
+	result := []model.TrialEnding{}
+	if to.Before(from) {
+		return result, nil
+	}
+
+	subs, err := s.trialStore.ListTrialsEnding(ctx, userId, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, sub := range subs {
+		result = append(result, model.NewTrialEnding(sub))
+	}
+	return result, nil
+}
+
+// ConvertTrials переводит в active подписки, пробный период которых закончился к текущему месяцу.
+// Возвращает число переведенных подписок; ошибка по одной подписке не останавливает остальные.
+func (s *ServiceStore) ConvertTrials(ctx context.Context) (int, error) {
+
+	subs, err := s.trialStore.ListTrialsEnded(ctx, model.MonthStart(time.Now()))
+	if err != nil {
+		return 0, err
+	}
+
+	converted := 0
+	var errs []error
+	for _, sub := range subs {
+		if err := s.applyTransition(ctx, &sub, model.StatusActive, "trial ended", sub.ConversionDate().Time); err != nil {
+			errs = append(errs, fmt.Errorf("subscription %s: %w", sub.ID, err))
+			continue
+		}
+		converted++
+	}
+	return converted, errors.Join(errs...)
+}
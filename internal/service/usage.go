@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"math"
+	"sort"
+	datatransfer "subscription/internal/api/dto"
+	"subscription/internal/model"
+	"time"
+)
+
+type UsageRepository interface {
+	RecordUsage(ctx context.Context, u model.Usage) (model.Usage, error)
+}
+
+// RecordUsage учитывает использование подписки: обновляет время последнего использования и счетчик
+func (s *ServiceStore) RecordUsage(ctx context.Context, id string, dto datatransfer.DTOUsage) (model.Usage, error) {
+
+	if _, err := s.subscriptionStore.GetByID(ctx, id); err != nil {
+		return model.Usage{}, err
+	}
+
+	usage, err := model.NewUsage(id, dto, time.Now())
+	if err != nil {
+		return model.Usage{}, err
+	}
+	return s.usageStore.RecordUsage(ctx, usage)
+}
+
+// Unused возвращает действующие подписки, которыми не пользовались последние days дней,
+// со стоимостью одного использования. Самые дорогие в месяц идут первыми.
+func (s *ServiceStore) Unused(ctx context.Context, userId string, days int) ([]model.UnusedSubscription, error) {
+
+	subs, err := s.subscriptionStore.GetAll(ctx, model.Filter{UserId: userId})
+	if err != nil {
+		return nil, err
+	}
+	return unused(subs, userId, days, time.Now().UTC()), nil
+}
+
+// unused отбирает подписки без использований после now - days
+func unused(subs []model.Subscription, userId string, days int, now time.Time) []model.UnusedSubscription {
+	cutoff := now.AddDate(0, 0, -days)
+	month := model.MonthStart(now)
+
+	result := []model.UnusedSubscription{}
+	for _, sub := range subs {
+		if sub.Status == model.StatusCancelled || sub.Status == model.StatusExpired {
+			continue
+		}
+		if sub.EndDate != nil && sub.EndDate.Time.Before(month) {
+			continue
+		}
+		since := sub.StartDate.Time
+		if sub.Usage != nil {
+			since = sub.Usage.LastUsedAt
+		}
+		if !since.Before(cutoff) {
+			continue
+		}
+
+		item := model.UnusedSubscription{
+			SubscriptionID: sub.ID,
+			ServiceName:    sub.ServiceName,
+			UserId:         sub.UserId,
+			DaysUnused:     int(now.Sub(since).Hours() / 24),
+			MonthlyCost:    sub.MonthlyCost(userId, month),
+			TotalCost:      total(charges([]model.Subscription{sub}, userId, sub.StartDate.Time, month)),
+		}
+		if sub.Usage != nil {
+			lastUsed := sub.Usage.LastUsedAt
+			item.LastUsedAt = &lastUsed
+			item.UseCount = sub.Usage.Count
+			if item.UseCount > 0 {
+				perUse := math.Round(float64(item.TotalCost)*100/float64(item.UseCount)) / 100
+				item.CostPerUse = &perUse
+			}
+		}
+		result = append(result, item)
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].MonthlyCost > result[j].MonthlyCost })
+	return result
+}
@@ -0,0 +1,99 @@
+package statement
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"subscription/internal/model"
+	"time"
+)
+
+// csvColumns названия колонок, которые встречаются в выгрузках банков
+var csvColumns = map[string][]string{
+	"date":        {"date", "дата", "дата операции", "дата платежа", "posted", "posting date", "transaction date"},
+	"amount":      {"amount", "сумма", "сумма операции", "сумма платежа"},
+	"description": {"description", "описание", "назначение", "payee", "merchant", "memo", "name", "контрагент"},
+}
+
+// csvDateLayouts форматы дат в выгрузках
+var csvDateLayouts = []string{time.DateOnly, "02.01.2006", "02.01.2006 15:04:05", "02/01/2006", time.RFC3339}
+
+var ErrCSVColumns = errors.New("csv statement must have date, amount and description columns")
+
+// ParseCSV разбирает выписку в CSV с заголовком. Разделитель (запятая или точка с запятой)
+// и колонки даты, суммы и описания определяются по первой строке.
+func ParseCSV(r io.Reader) ([]model.Transaction, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	first, _, _ := bytes.Cut(header, []byte("\n"))
+
+	reader := csv.NewReader(br)
+	reader.FieldsPerRecord = -1
+	if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrCSVColumns
+	}
+
+	index := make(map[string]int)
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		for column, aliases := range csvColumns {
+			if _, ok := index[column]; ok {
+				continue
+			}
+			for _, alias := range aliases {
+				if name == alias {
+					index[column] = i
+				}
+			}
+		}
+	}
+	if len(index) != len(csvColumns) {
+		return nil, ErrCSVColumns
+	}
+
+	var txns []model.Transaction
+	for line, record := range records[1:] {
+		if len(record) <= max(index["date"], index["amount"], index["description"]) {
+			continue
+		}
+		date, err := parseCSVDate(record[index["date"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line+2, record[index["date"]])
+		}
+		amount, err := parseAmount(record[index["amount"]])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid amount %q", line+2, record[index["amount"]])
+		}
+		txns = append(txns, model.Transaction{
+			Date:        date,
+			Amount:      amount,
+			Description: strings.TrimSpace(record[index["description"]]),
+		})
+	}
+	return txns, nil
+}
+
+func parseCSVDate(s string) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	for _, layout := range csvDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("unknown date format")
+}
@@ -0,0 +1,91 @@
+package statement
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"subscription/internal/model"
+	"time"
+)
+
+var ErrOFX = errors.New("ofx statement has no transactions")
+
+// ParseOFX разбирает выписку OFX: как SGML-вариант 1.x без закрывающих тегов, так и XML-вариант 2.x.
+// Из каждой операции STMTTRN берутся DTPOSTED, TRNAMT и NAME (или MEMO).
+func ParseOFX(r io.Reader) ([]model.Transaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// Теги ищутся без учета регистра в копии, где заглавными сделаны только латинские буквы:
+	// длина в байтах не меняется, и найденные смещения подходят для исходного текста
+	body := string(data)
+	upper := asciiUpper(body)
+
+	var txns []model.Transaction
+	for pos := 0; ; {
+		start := strings.Index(upper[pos:], "<STMTTRN>")
+		if start < 0 {
+			break
+		}
+		pos += start + len("<STMTTRN>")
+		end := strings.Index(upper[pos:], "</STMTTRN>")
+		if end < 0 {
+			end = len(upper) - pos
+		}
+		block, blockUpper := body[pos:pos+end], upper[pos:pos+end]
+		pos += end
+
+		date, err := parseOFXDate(ofxValue(block, blockUpper, "DTPOSTED"))
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: invalid DTPOSTED", len(txns)+1)
+		}
+		amount, err := parseAmount(ofxValue(block, blockUpper, "TRNAMT"))
+		if err != nil {
+			return nil, fmt.Errorf("transaction %d: invalid TRNAMT", len(txns)+1)
+		}
+		description := ofxValue(block, blockUpper, "NAME")
+		if description == "" {
+			description = ofxValue(block, blockUpper, "MEMO")
+		}
+		txns = append(txns, model.Transaction{Date: date, Amount: amount, Description: description})
+	}
+	if len(txns) == 0 {
+		return nil, ErrOFX
+	}
+	return txns, nil
+}
+
+// ofxValue возвращает значение тега внутри блока: текст до следующего тега или конца строки.
+// upper — тот же блок после asciiUpper
+func ofxValue(block, upper, tag string) string {
+	i := strings.Index(upper, "<"+tag+">")
+	if i < 0 {
+		return ""
+	}
+	value := block[i+len(tag)+2:]
+	if j := strings.IndexAny(value, "<\r\n"); j >= 0 {
+		value = value[:j]
+	}
+	return strings.TrimSpace(value)
+}
+
+// asciiUpper переводит в верхний регистр только латинские буквы, сохраняя длину строки в байтах
+func asciiUpper(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if 'a' <= c && c <= 'z' {
+			b[i] = c - 'a' + 'A'
+		}
+	}
+	return string(b)
+}
+
+// parseOFXDate разбирает дату OFX вида YYYYMMDD[HHMMSS[.XXX]][TZ], время и зона отбрасываются
+func parseOFXDate(s string) (time.Time, error) {
+	if len(s) < 8 {
+		return time.Time{}, errors.New("short date")
+	}
+	return time.Parse("20060102", s[:8])
+}
@@ -0,0 +1,104 @@
+// Package statement разбирает выписки по банковским счетам и картам
+package statement
+
+import (
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"subscription/internal/model"
+)
+
+// Format формат файла выписки
+type Format string
+
+const (
+	FormatCSV Format = "csv"
+	FormatOFX Format = "ofx"
+)
+
+var ErrFormat = errors.New("statement format must be one of: csv, ofx")
+
+// DetectFormat определяет формат по явному значению или расширению файла
+func DetectFormat(format, filename string) (Format, error) {
+	if format == "" {
+		name := strings.ToLower(filename)
+		switch {
+		case strings.HasSuffix(name, ".csv"):
+			format = "csv"
+		case strings.HasSuffix(name, ".ofx"), strings.HasSuffix(name, ".qfx"):
+			format = "ofx"
+		}
+	}
+	switch Format(strings.ToLower(format)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatOFX:
+		return FormatOFX, nil
+	}
+	return "", ErrFormat
+}
+
+// Parse разбирает выписку в указанном формате и возвращает списания.
+// Поступления на счет пропускаются.
+func Parse(r io.Reader, format Format) ([]model.Transaction, error) {
+	var (
+		txns []model.Transaction
+		err  error
+	)
+	switch format {
+	case FormatCSV:
+		txns, err = ParseCSV(r)
+	case FormatOFX:
+		txns, err = ParseOFX(r)
+	default:
+		return nil, ErrFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	return debits(txns), nil
+}
+
+// debits оставляет списания с положительной суммой. Если в выписке нет отрицательных сумм,
+// все операции считаются списаниями: так выгружают выписки по кредитным картам.
+func debits(txns []model.Transaction) []model.Transaction {
+	negative := false
+	for _, t := range txns {
+		if t.Amount < 0 {
+			negative = true
+			break
+		}
+	}
+
+	result := make([]model.Transaction, 0, len(txns))
+	for _, t := range txns {
+		if negative {
+			if t.Amount >= 0 {
+				continue
+			}
+			t.Amount = -t.Amount
+		}
+		if t.Amount > 0 {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// parseAmount разбирает сумму с точкой или запятой в дробной части, пробелами между разрядами
+// и знаком минус, округляя до целых рублей
+func parseAmount(s string) (int, error) {
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u2212", "-", "+", "").Replace(strings.TrimSpace(s))
+	if i, j := strings.LastIndex(s, ","), strings.LastIndex(s, "."); i > j {
+		s = strings.ReplaceAll(s[:i], ".", "") + "." + s[i+1:]
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(f)), nil
+}
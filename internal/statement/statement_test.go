@@ -0,0 +1,87 @@
+package statement
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCSV_Unit(t *testing.T) {
+	data := "\ufeffДата операции;Сумма;Описание\n" +
+		"05.01.2024;-799,00;NETFLIX.COM 866-579\n" +
+		"06.01.2024;50 000,00;Зарплата\n" +
+		"07.01.2024;-1 299,50;ПЕРЕКРЕСТОК\n"
+
+	txns, err := Parse(strings.NewReader(data), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 2 {
+		t.Fatalf("expected 2 debits, got %+v", txns)
+	}
+	if txns[0].Amount != 799 || txns[0].Description != "NETFLIX.COM 866-579" || !txns[0].Date.Equal(time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected first transaction: %+v", txns[0])
+	}
+	if txns[1].Amount != 1300 {
+		t.Fatalf("expected amount rounded to 1300, got %d", txns[1].Amount)
+	}
+
+	if _, err := ParseCSV(strings.NewReader("when,how much\n2024-01-01,10\n")); err != ErrCSVColumns {
+		t.Fatalf("expected columns error, got %v", err)
+	}
+}
+
+func TestParseOFX_Unit(t *testing.T) {
+	data := `OFXHEADER:100
+DATA:OFXSGML
+<OFX><BANKMSGSRSV1><STMTTRNRS><STMTRS><BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20240110120000[+3:MSK]
+<TRNAMT>-199.00
+<NAME>SPOTIFY P1A2B3
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CREDIT
+<DTPOSTED>20240111
+<TRNAMT>1000.00
+<MEMO>Refund
+</STMTTRN>
+</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1></OFX>`
+
+	txns, err := Parse(strings.NewReader(data), FormatOFX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 1 || txns[0].Amount != 199 || txns[0].Description != "SPOTIFY P1A2B3" || txns[0].Date.Day() != 10 {
+		t.Fatalf("unexpected transactions: %+v", txns)
+	}
+}
+
+func TestParseOFXNonASCII_Unit(t *testing.T) {
+	// Заглавная "ɐ" длиннее строчной в байтах: смещения тегов не должны съезжать
+	data := `<OFX><SIGNONMSGSRSV1><SONRS><FI><ORG>ɐɐɐ банк</ORG></FI></SONRS></SIGNONMSGSRSV1>
+<stmttrn><memo>ɐɐɐɐɐɐ<dtposted>20240110<trnamt>-299.00<name>Яндекс Плюс</name></stmttrn>
+<stmttrn><trntype>DEBIT<dtposted>20240210<trnamt>-299.00<name>ɐ Okko</name></stmttrn>
+</OFX>`
+
+	txns, err := Parse(strings.NewReader(data), FormatOFX)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txns) != 2 || txns[0].Description != "Яндекс Плюс" || txns[1].Description != "ɐ Okko" || txns[1].Date.Month() != 2 {
+		t.Fatalf("unexpected transactions: %+v", txns)
+	}
+}
+
+func TestDetectFormat_Unit(t *testing.T) {
+	if f, err := DetectFormat("", "Statement.QFX"); err != nil || f != FormatOFX {
+		t.Fatalf("expected ofx by extension, got %s, %v", f, err)
+	}
+	if f, err := DetectFormat("CSV", "export.txt"); err != nil || f != FormatCSV {
+		t.Fatalf("expected explicit csv, got %s, %v", f, err)
+	}
+	if _, err := DetectFormat("", "export.txt"); err != ErrFormat {
+		t.Fatalf("expected format error, got %v", err)
+	}
+}
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS subscription_member;
+ALTER TABLE subscription
+    DROP COLUMN IF EXISTS split_type,
+    DROP COLUMN IF EXISTS household_id;
+DROP TABLE IF EXISTS household_member;
+DROP TABLE IF EXISTS household;
@@ -0,0 +1,21 @@
+CREATE TABLE household (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL
+);
+
+CREATE TABLE household_member (
+    household_id UUID NOT NULL REFERENCES household(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    PRIMARY KEY (household_id, user_id)
+);
+
+ALTER TABLE subscription
+    ADD COLUMN household_id UUID REFERENCES household(id) ON DELETE SET NULL,
+    ADD COLUMN split_type TEXT NOT NULL DEFAULT 'equal';
+
+CREATE TABLE subscription_member (
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    share INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (subscription_id, user_id)
+);
@@ -0,0 +1,8 @@
+CREATE TABLE budget (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    service_name TEXT NOT NULL DEFAULT '',
+    monthly_limit INT NOT NULL
+);
+
+CREATE INDEX budget_user_id_idx ON budget (user_id);
@@ -0,0 +1,6 @@
+ALTER TABLE budget
+    DROP COLUMN IF EXISTS category;
+DROP TABLE IF EXISTS subscription_tag;
+DROP TABLE IF EXISTS tag;
+ALTER TABLE subscription
+    DROP COLUMN IF EXISTS category;
@@ -0,0 +1,16 @@
+ALTER TABLE subscription
+    ADD COLUMN category TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE tag (
+    id SERIAL PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE subscription_tag (
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    tag_id INT NOT NULL REFERENCES tag(id) ON DELETE CASCADE,
+    PRIMARY KEY (subscription_id, tag_id)
+);
+
+ALTER TABLE budget
+    ADD COLUMN category TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE subscription
+    DROP COLUMN IF EXISTS catalog_id;
+DROP TABLE IF EXISTS catalog_service;
@@ -0,0 +1,11 @@
+CREATE TABLE catalog_service (
+    id UUID PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    aliases TEXT[] NOT NULL DEFAULT '{}',
+    default_price INT NOT NULL DEFAULT 0,
+    category TEXT NOT NULL DEFAULT '',
+    website TEXT NOT NULL DEFAULT ''
+);
+
+ALTER TABLE subscription
+    ADD COLUMN catalog_id UUID REFERENCES catalog_service(id) ON DELETE SET NULL;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS subscription_plan_change;
+DROP TABLE IF EXISTS subscription_price;
+ALTER TABLE subscription
+    DROP COLUMN IF EXISTS plan_id;
+DROP TABLE IF EXISTS plan;
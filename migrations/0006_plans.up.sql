@@ -0,0 +1,28 @@
+CREATE TABLE plan (
+    id UUID PRIMARY KEY,
+    catalog_id UUID NOT NULL REFERENCES catalog_service(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    price INT NOT NULL,
+    UNIQUE (catalog_id, name)
+);
+
+ALTER TABLE subscription
+    ADD COLUMN plan_id UUID REFERENCES plan(id) ON DELETE SET NULL;
+
+CREATE TABLE subscription_price (
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    effective_from DATE NOT NULL,
+    price INT NOT NULL,
+    PRIMARY KEY (subscription_id, effective_from)
+);
+
+CREATE TABLE subscription_plan_change (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    from_plan_id UUID REFERENCES plan(id) ON DELETE SET NULL,
+    to_plan_id UUID REFERENCES plan(id) ON DELETE SET NULL,
+    old_price INT NOT NULL,
+    new_price INT NOT NULL,
+    effective_date DATE NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
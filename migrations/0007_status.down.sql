@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS subscription_status_change;
+ALTER TABLE subscription
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,14 @@
+ALTER TABLE subscription
+    ADD COLUMN status TEXT NOT NULL DEFAULT 'active';
+
+CREATE TABLE subscription_status_change (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    effective_date DATE NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX subscription_status_change_subscription_id_idx ON subscription_status_change (subscription_id);
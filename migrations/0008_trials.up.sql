@@ -0,0 +1,2 @@
+ALTER TABLE subscription
+    ADD COLUMN trial_end_date DATE;
@@ -0,0 +1,11 @@
+CREATE TABLE subscription_discount (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    value INT NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE,
+    code TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX subscription_discount_subscription_id_idx ON subscription_discount (subscription_id);
@@ -0,0 +1,10 @@
+CREATE TABLE job_run (
+    name TEXT PRIMARY KEY,
+    schedule TEXT NOT NULL,
+    last_started_at TIMESTAMPTZ,
+    last_finished_at TIMESTAMPTZ,
+    last_status TEXT NOT NULL DEFAULT '',
+    last_result TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    next_run_at TIMESTAMPTZ NOT NULL
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS reminder_sent;
+DROP TABLE IF EXISTS reminder_setting;
@@ -0,0 +1,17 @@
+CREATE TABLE reminder_setting (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT '',
+    lead_days INT NOT NULL
+);
+
+CREATE INDEX reminder_setting_subscription_id_idx ON reminder_setting (subscription_id);
+
+CREATE TABLE reminder_sent (
+    setting_id UUID NOT NULL REFERENCES reminder_setting(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    due_date DATE NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (setting_id, kind, due_date)
+);
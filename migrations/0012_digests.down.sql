@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS digest_sent;
+DROP TABLE IF EXISTS digest_setting;
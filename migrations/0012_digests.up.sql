@@ -0,0 +1,16 @@
+CREATE TABLE digest_setting (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    period TEXT NOT NULL,
+    channel TEXT NOT NULL,
+    target TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX digest_setting_user_id_idx ON digest_setting (user_id);
+
+CREATE TABLE digest_sent (
+    setting_id UUID NOT NULL REFERENCES digest_setting(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (setting_id, period_start)
+);
@@ -0,0 +1 @@
+ALTER TABLE subscription ADD COLUMN billing_cycle TEXT NOT NULL DEFAULT 'monthly';
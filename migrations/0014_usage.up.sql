@@ -0,0 +1,6 @@
+CREATE TABLE subscription_usage (
+    subscription_id UUID PRIMARY KEY REFERENCES subscription(id) ON DELETE CASCADE,
+    first_used_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ NOT NULL,
+    use_count INT NOT NULL DEFAULT 0
+);
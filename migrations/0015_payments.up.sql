@@ -0,0 +1,11 @@
+CREATE TABLE payment (
+    id UUID PRIMARY KEY,
+    subscription_id UUID NOT NULL REFERENCES subscription(id) ON DELETE CASCADE,
+    amount INT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'RUB',
+    paid_at DATE NOT NULL,
+    method TEXT NOT NULL DEFAULT '',
+    reference TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX payment_subscription_id_paid_at_idx ON payment (subscription_id, paid_at);
@@ -0,0 +1,16 @@
+CREATE TABLE subscription_proposal (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    merchant TEXT NOT NULL,
+    service_name TEXT NOT NULL,
+    price INT NOT NULL,
+    billing_cycle TEXT NOT NULL,
+    start_date DATE NOT NULL,
+    last_charged DATE NOT NULL,
+    occurrences INT NOT NULL,
+    matched BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL DEFAULT 'pending',
+    subscription_id UUID REFERENCES subscription(id) ON DELETE SET NULL
+);
+
+CREATE INDEX subscription_proposal_user_id_idx ON subscription_proposal (user_id, status);
@@ -0,0 +1,6 @@
+CREATE TABLE journal_account (
+    user_id UUID PRIMARY KEY,
+    default_account TEXT NOT NULL,
+    funding_account TEXT NOT NULL,
+    category_accounts JSONB NOT NULL DEFAULT '{}'
+);